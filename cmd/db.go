@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/matheusbucater/gmess/internal/db/migrations"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+func showMigrationStatus(ctx context.Context) error {
+	db, err := utils.DbOpen(ctx)
+	if err != nil { return err }
+	defer db.Close()
+
+	for _, src := range migrations.Sources() {
+		status, err := migrations.GetStatus(ctx, db, src)
+		if err != nil { return err }
+
+		fmt.Printf("%s (version %d)\n", status.Source, status.Version)
+		for _, m := range status.Migrations {
+			mark := " "
+			if m.Version <= status.Version { mark = "x" }
+			fmt.Printf("\t[%s] %06d %s\n", mark, m.Version, m.Name)
+		}
+	}
+	return nil
+}
+
+func migrate(ctx context.Context, action string, args []string) error {
+	db, err := utils.DbOpen(ctx)
+	if err != nil { return err }
+	defer db.Close()
+
+	// down and goto work on a single source, the core one unless its name
+	// is given as the last argument.
+	sourceFromArgs := func(i int) (migrations.Source, error) {
+		if len(args) <= i { return migrations.Core, nil }
+		src, ok := migrations.GetSource(args[i])
+		if !ok { return src, fmt.Errorf("unknown migrations source \"%s\"", args[i]) }
+		return src, nil
+	}
+
+	switch action {
+	case "up":
+		for _, src := range migrations.Sources() {
+			if err := migrations.Up(ctx, db, src); err != nil { return err }
+		}
+		return nil
+	case "down":
+		src, err := sourceFromArgs(0)
+		if err != nil { return err }
+		return migrations.Down(ctx, db, src)
+	case "goto":
+		if len(args) < 1 { return fmt.Errorf("expected 'goto N [source]'") }
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil { return fmt.Errorf("invalid version \"%s\"", args[0]) }
+		src, err := sourceFromArgs(1)
+		if err != nil { return err }
+		return migrations.Goto(ctx, db, src, version)
+	}
+	return fmt.Errorf("invalid migrate action \"%s\"", action)
+}
+
+func dbCmd(args []string) {
+	if len(args) < 2 || args[0] != "migrate" {
+		fmt.Println("expected 'db migrate up|down [source]|status|goto N [source]'")
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	action := args[1]
+
+	if action != "status" {
+		if err := migrate(ctx, action, args[2:]); err != nil {
+			fmt.Printf("error migrating database: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if err := showMigrationStatus(ctx); err != nil {
+		fmt.Printf("error showing migration status: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -12,10 +12,11 @@ import (
 	"time"
 
 	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/doctor"
 	"github.com/matheusbucater/gmess/internal/feat"
-	"github.com/matheusbucater/gmess/internal/feat/lists"
-	"github.com/matheusbucater/gmess/internal/feat/notifications"
-	"github.com/matheusbucater/gmess/internal/feat/todos"
+	_ "github.com/matheusbucater/gmess/internal/feat/lists"
+	_ "github.com/matheusbucater/gmess/internal/feat/notifications"
+	_ "github.com/matheusbucater/gmess/internal/feat/todos"
 	"github.com/matheusbucater/gmess/internal/utils"
 
 	_ "modernc.org/sqlite"
@@ -71,11 +72,11 @@ func showMessages(order string, sort string) error {
 	fmt.Println(sb.String())
 
 	for _, message := range messages {
-		features, err := queries.GetPrettyFeaturesByMessageId(ctx, message.ID)
+		badges, err := feat.MessageBadges(ctx, queries, message.ID)
 		if err != nil { return err }
 
 		fmt.Printf("(%d) %s", message.ID, message.Text)
-		if len(features) > 0 { fmt.Printf(" [%s]", features) }
+		if len(badges) > 0 { fmt.Printf(" [%s]", strings.Join(badges, ", ")) }
 		fmt.Println()
 	}
 	return nil
@@ -92,18 +93,39 @@ func showMessageDetails(id int64) error {
 	if err != nil { return err }
 	if exists == 0 { return errors.New("Invalid message ID") }
 
-	message_details, err := queries.GetMessageAndFeatures(ctx, id)
+	message, err := queries.GetMessageById(ctx, id)
 	if err != nil { return err }
 
+	features, err := queries.GetFeaturesByMessageId(ctx, id)
+	if err != nil { return err }
+
+	featureNames := []string{}
+	for _, f := range features {
+		if f.Count > 0 { featureNames = append(featureNames, f.FeatureName) }
+	}
+
 	fmt.Println("Message details:")
 	fmt.Printf(
 		"\tid: %d\n\ttext: %s\n\tcreated_at: %s\n\tupdated_at: %s\n\tfeatures: %s\n", 
-		message_details.ID, message_details.Text,
-		utils.LocalizeDateTime(message_details.CreatedAt),
-		utils.LocalizeDateTime(message_details.UpdatedAt),
-		message_details.Features,
+		message.ID, message.Text,
+		utils.LocalizeDateTime(message.CreatedAt),
+		utils.LocalizeDateTime(message.UpdatedAt),
+		strings.Join(featureNames, ", "),
 	)
 
+	for _, name := range featureNames {
+		feature, ok := feat.Get(name)
+		if !ok { continue }
+
+		details, err := feature.Details(ctx, queries, id)
+		if err != nil { return err }
+
+		fmt.Printf("\t%s:\n", name)
+		for line := range strings.SplitSeq(details, "\n") {
+			fmt.Printf("\t  %s\n", line)
+		}
+	}
+
 	return nil
 }
 
@@ -145,13 +167,33 @@ func deleteMessage(id int64) error {
 	if err != nil { return err }
 	if (exists == 0) { return errors.New("Invalid message ID") }
 	
-	if err = queries.DeleteMessage(ctx, id); err != nil { return err }
-	return nil
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil { return err }
+	qtx := queries.WithTx(tx)
+
+	for _, feature := range feat.All() {
+		if err = feature.OnMessageDelete(ctx, qtx, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err = qtx.DeleteMessage(ctx, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func main() {
 	time.Local, _ = time.LoadLocation("America/Sao_Paulo")
 
+	dbFlag := flag.String("db", "", "path to the database file (overrides GMESS_DB)")
+	profileFlag := flag.String("profile", "", "use the database of a named profile (overrides GMESS_PROFILE)")
+	flag.Parse()
+	args := flag.Args()
+
 	helloCmd := flag.NewFlagSet("hello", flag.ExitOnError)
 	helloNameFlag := helloCmd.String("name", "", "name to be helloed")
 
@@ -171,14 +213,29 @@ func main() {
 	deleteCmd := flag.NewFlagSet("delete", flag.ExitOnError)
 	deleteIdFlag := deleteCmd.Int64("id", -1, "id of the message to be deleted")
 
-	if len(os.Args) < 2 {
-		fmt.Println("expected 'hello', 'show', 'create', 'update', 'delete' or [feature] subcommand.")
+	if len(args) < 1 {
+		fmt.Println("expected 'hello', 'show', 'create', 'update', 'delete', 'db', 'doctor' or [feature] subcommand.")
 		os.Exit(1)
 	}
 
-	switch os.Args[1] {
+	path, err := utils.ResolveDbPath(*dbFlag, *profileFlag)
+	if err != nil {
+		fmt.Printf("error resolving database path: %s\n", err)
+		os.Exit(1)
+	}
+	utils.SetDbPath(path)
+	utils.SetProfile(utils.ResolveProfile(*dbFlag, *profileFlag))
+
+	if args[0] != "db" {
+		if err := feat.SyncFeatures(); err != nil {
+			fmt.Printf("error syncing features: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	switch args[0] {
 	case "hello":
-		err := helloCmd.Parse(os.Args[2:])
+		err := helloCmd.Parse(args[1:])
 		if err != nil {
 			fmt.Printf("error parsing cli args: %s\n", err)
 			os.Exit(1)
@@ -187,7 +244,7 @@ func main() {
 
 		fmt.Printf("Hello %s!\n", *helloNameFlag)
 	case "show":
-		err := showCmd.Parse(os.Args[2:])
+		err := showCmd.Parse(args[1:])
 		if err != nil {
 			fmt.Printf("error parsing cli args: %s\n", err)
 			os.Exit(1)
@@ -222,7 +279,7 @@ func main() {
 			}
 		}
 	case "create":
-		if err := createCmd.Parse(os.Args[2:]); err != nil {
+		if err := createCmd.Parse(args[1:]); err != nil {
 			fmt.Printf("error parsing cli args: %s\n", err)
 		}
 		utils.EnforceRequiredFlags(createCmd, []string{"message"})
@@ -232,7 +289,7 @@ func main() {
 		}
 		fmt.Println("message created.")
 	case "update":
-		err := updateCmd.Parse(os.Args[2:])
+		err := updateCmd.Parse(args[1:])
 		if err != nil {
 			fmt.Printf("error parsing cli args: %s\n", err)
 			os.Exit(1)
@@ -244,7 +301,7 @@ func main() {
 		}
 		fmt.Printf("message (%d) udpated\n", *updateIdFlag)
 	case "delete":
-		if err := deleteCmd.Parse(os.Args[2:]); err != nil {
+		if err := deleteCmd.Parse(args[1:]); err != nil {
 			fmt.Printf("error parsing cli args: %s\n", err)
 			os.Exit(1)
 		}
@@ -254,29 +311,19 @@ func main() {
 			os.Exit(1)
 		}
 		fmt.Println("Message deleted.")
+	case "db":
+		dbCmd(args[1:])
+	case "doctor":
+		doctor.Cmd(args[1:])
 	default:
-		exists, err := feat.FeatureExists(os.Args[1])
-		if err != nil {
-			fmt.Printf("error checking feature existence: %s\n", err)
-			os.Exit(1)
-		}
-		if !exists {
-			fmt.Printf("feature \"%s\" not available.\n", os.Args[1])
+		feature, ok := feat.Get(args[0])
+		if !ok {
+			fmt.Printf("feature \"%s\" not available.\n", args[0])
 			flag.Usage() // TODO: make a proper default usage
 			os.Exit(1)
 		}
 
-		switch os.Args[1] {
-		case feat.E_notifications_feature.String():
-			notifications.Cmd(os.Args[2:])
-		case feat.E_todos_feature.String():
-			todos.Cmd(os.Args[2:])
-		case feat.E_todos_feature.String():
-			lists.Cmd(os.Args[2:])
-		default:
-			fmt.Println("Invalid command")
-			os.Exit(1)
-		}
+		feature.Cmd(args[1:])
 	}
 }
 
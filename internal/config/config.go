@@ -0,0 +1,371 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is read from config.json in the config dir. Everything in it is
+// optional and a missing file is the same as an empty one.
+//
+//	{
+//	  "quiet_hours": [{"from": "22:00", "to": "07:00"}, {"days": ["sat", "sun"]}],
+//	  "sink": "exec:notify-send gmess \"$GMESS_TEXT\"",
+//	  "profiles": {
+//	    "work": {"quiet_hours": [{"from": "18:00", "to": "09:00"}], "sink": "syslog"}
+//	  },
+//	  "webhooks": {
+//	    "relay": {"url": "http://localhost:8080/hook", "secret": "s3cr3t", "timeout": "5s"}
+//	  },
+//	  "emails": {
+//	    "me": {"host": "smtp.example.com", "username": "me", "password": "...", "from": "me@example.com", "to": ["me@example.com"]}
+//	  },
+//	  "channels": {"minor": ["stdout"], "deadlines": ["webhook:relay", "email:me"]},
+//	  "default_channel": "minor",
+//	  "routes": {"cron": "deadlines"},
+//	  "template": "{{.Text}}{{if .Late}} ({{duration .Late}} late){{end}}",
+//	  "channel_templates": {"deadlines": "DUE: {{.Text}}"}
+//	}
+//
+// Channels are named lists of sinks. Notifications go to their own channel,
+// or to the one routed for their type, or to the default channel.
+// Notifications are rendered with their own template, or their channel's,
+// or the global one.
+type Config struct {
+	QuietHours       QuietHours          `json:"quiet_hours"`
+	Sink             string              `json:"sink"`
+	Channels         map[string][]string `json:"channels"`
+	DefaultChannel   string              `json:"default_channel"`
+	Routes           map[string]string   `json:"routes"`
+	Template         string              `json:"template"`
+	ChannelTemplates map[string]string   `json:"channel_templates"`
+	Profiles         map[string]Profile  `json:"profiles"`
+	Webhooks         map[string]Webhook  `json:"webhooks"`
+	Emails           map[string]Email    `json:"emails"`
+}
+
+// Profile holds the settings that only apply to one profile, on top of the
+// global ones.
+type Profile struct {
+	QuietHours     QuietHours `json:"quiet_hours"`
+	Sink           string     `json:"sink"`
+	DefaultChannel string     `json:"default_channel"`
+}
+
+// Dir returns the directory where gmess looks for its config:
+// $XDG_CONFIG_HOME/gmess, falling back to ~/.config/gmess.
+func Dir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gmess"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil { return "", err }
+
+	return filepath.Join(home, ".config", "gmess"), nil
+}
+
+// Load reads and validates the config file.
+func Load() (Config, error) {
+	dir, err := Dir()
+	if err != nil { return Config{}, err }
+
+	path := filepath.Join(dir, "config.json")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) { return Config{}, nil }
+	if err != nil { return Config{}, err }
+
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := c.QuietHours.check(); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	for name, p := range c.Profiles {
+		if err := p.QuietHours.check(); err != nil {
+			return Config{}, fmt.Errorf("%s: profile \"%s\": %w", path, name, err)
+		}
+		if err := c.checkChannel(p.DefaultChannel); err != nil {
+			return Config{}, fmt.Errorf("%s: profile \"%s\": %w", path, name, err)
+		}
+	}
+	for name, sinks := range c.Channels {
+		if len(sinks) == 0 {
+			return Config{}, fmt.Errorf("%s: channel \"%s\" has no sinks", path, name)
+		}
+	}
+	if err := c.checkChannel(c.DefaultChannel); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	for notificationType, channel := range c.Routes {
+		if err := c.checkChannel(channel); err != nil {
+			return Config{}, fmt.Errorf("%s: route \"%s\": %w", path, notificationType, err)
+		}
+	}
+	for channel := range c.ChannelTemplates {
+		if err := c.checkChannel(channel); err != nil {
+			return Config{}, fmt.Errorf("%s: channel template: %w", path, err)
+		}
+	}
+	for name, w := range c.Webhooks {
+		if err := w.check(); err != nil {
+			return Config{}, fmt.Errorf("%s: webhook \"%s\": %w", path, name, err)
+		}
+	}
+	for name, e := range c.Emails {
+		if err := e.check(); err != nil {
+			return Config{}, fmt.Errorf("%s: email \"%s\": %w", path, name, err)
+		}
+	}
+	return c, nil
+}
+
+// Quiet returns the quiet hours of the profile: the global windows followed
+// by the profile's own.
+func (c Config) Quiet(profile string) QuietHours {
+	return append(slices.Clone(c.QuietHours), c.Profiles[profile].QuietHours...)
+}
+
+// SinkFor returns where notifications of the profile are sent: its own
+// sink, the global one or "stdout".
+func (c Config) SinkFor(profile string) string {
+	if sink := c.Profiles[profile].Sink; sink != "" { return sink }
+	if c.Sink != "" { return c.Sink }
+	return "stdout"
+}
+
+func (c Config) checkChannel(name string) error {
+	if _, ok := c.Channels[name]; name != "" && !ok {
+		return fmt.Errorf("channel \"%s\" does not exist", name)
+	}
+	return nil
+}
+
+// Route returns the channel notifications of the type go to when they don't
+// have one of their own: the one routed for the type, or the default channel
+// of the profile, or the global one. It's "" when there's none.
+func (c Config) Route(profile string, notificationType string) string {
+	if channel := c.Routes[notificationType]; channel != "" { return channel }
+	if channel := c.Profiles[profile].DefaultChannel; channel != "" { return channel }
+	return c.DefaultChannel
+}
+
+// Retries says how failed sends are retried: after Backoff (e.g. "30s"),
+// twice as long after every other failure, until MaxAttempts were made.
+type Retries struct {
+	MaxAttempts int    `json:"max_attempts"`
+	Backoff     string `json:"backoff"`
+}
+
+const (
+	defaultMaxAttempts = 5
+	defaultBackoff     = 30 * time.Second
+	defaultTimeout     = 10 * time.Second
+)
+
+func (r Retries) check() error {
+	if err := checkDuration(r.Backoff); err != nil { return err }
+	if r.MaxAttempts < 0 { return fmt.Errorf("invalid max_attempts %d", r.MaxAttempts) }
+	return nil
+}
+
+// BackoffOrDefault returns how long to wait before the first retry.
+func (r Retries) BackoffOrDefault() time.Duration {
+	return durationOr(r.Backoff, defaultBackoff)
+}
+
+// MaxAttemptsOrDefault returns how many sends are tried before giving up.
+func (r Retries) MaxAttemptsOrDefault() int {
+	if r.MaxAttempts > 0 { return r.MaxAttempts }
+	return defaultMaxAttempts
+}
+
+func checkDuration(d string) error {
+	if d == "" { return nil }
+	if v, err := time.ParseDuration(d); err != nil || v <= 0 {
+		return fmt.Errorf("invalid duration \"%s\"", d)
+	}
+	return nil
+}
+
+func durationOr(d string, fallback time.Duration) time.Duration {
+	if v, err := time.ParseDuration(d); err == nil && v > 0 { return v }
+	return fallback
+}
+
+// Webhook is where a "webhook:NAME" sink posts notifications. Timeout is a
+// duration, 10s by default.
+type Webhook struct {
+	URL     string            `json:"url"`
+	Secret  string            `json:"secret"`
+	Headers map[string]string `json:"headers"`
+	Timeout string            `json:"timeout"`
+	Retries
+}
+
+func (w Webhook) check() error {
+	if !strings.HasPrefix(w.URL, "http://") && !strings.HasPrefix(w.URL, "https://") {
+		return fmt.Errorf("invalid url \"%s\", expected http:// or https://", w.URL)
+	}
+	if err := checkDuration(w.Timeout); err != nil { return err }
+	return w.Retries.check()
+}
+
+// TimeoutOrDefault returns how long a post may take.
+func (w Webhook) TimeoutOrDefault() time.Duration {
+	return durationOr(w.Timeout, defaultTimeout)
+}
+
+// Email is the SMTP server and addresses an "email:NAME" sink sends
+// notifications with. Port is 587 by default. STARTTLS is used whenever the
+// server offers it, and is required with RequireTLS or to authenticate
+// anywhere but on localhost. Subject is rendered like the notifications'
+// templates, "gmess: {{.Text}}" by default.
+type Email struct {
+	Host       string   `json:"host"`
+	Port       int      `json:"port"`
+	Username   string   `json:"username"`
+	Password   string   `json:"password"`
+	RequireTLS bool     `json:"require_tls"`
+	From       string   `json:"from"`
+	To         []string `json:"to"`
+	Subject    string   `json:"subject"`
+	Timeout    string   `json:"timeout"`
+	Retries
+}
+
+const defaultSubject = "gmess: {{.Text}}"
+
+func (e Email) check() error {
+	if e.Host == "" { return errors.New("missing host") }
+	if e.Port < 0 || e.Port > 65535 { return fmt.Errorf("invalid port %d", e.Port) }
+	if _, err := mail.ParseAddress(e.From); err != nil { return fmt.Errorf("invalid from \"%s\": %w", e.From, err) }
+	if len(e.To) == 0 { return errors.New("missing to") }
+	for _, to := range e.To {
+		if _, err := mail.ParseAddress(to); err != nil { return fmt.Errorf("invalid to \"%s\": %w", to, err) }
+	}
+	if err := checkDuration(e.Timeout); err != nil { return err }
+	return e.Retries.check()
+}
+
+// Addr returns the host:port of the SMTP server.
+func (e Email) Addr() string {
+	port := e.Port
+	if port == 0 { port = 587 }
+	return net.JoinHostPort(e.Host, strconv.Itoa(port))
+}
+
+// SubjectOrDefault returns the subject template.
+func (e Email) SubjectOrDefault() string {
+	if e.Subject != "" { return e.Subject }
+	return defaultSubject
+}
+
+// TimeoutOrDefault returns how long sending an email may take.
+func (e Email) TimeoutOrDefault() time.Duration {
+	return durationOr(e.Timeout, defaultTimeout)
+}
+
+// Window is a span of quiet hours. From and To are "HH:MM"; a window whose
+// To isn't after its From ends on the next day, and one without either
+// takes the whole day. Days ("mon" to "sun") are the days the window starts
+// on, every day when empty.
+type Window struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Days []string `json:"days"`
+}
+
+type QuietHours []Window
+
+var weekDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+func clock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil { return 0, fmt.Errorf("invalid time \"%s\", expected HH:MM", s) }
+	return time.Duration(t.Hour()) * time.Hour + time.Duration(t.Minute()) * time.Minute, nil
+}
+
+func (q QuietHours) check() error {
+	for _, w := range q {
+		if (w.From == "") != (w.To == "") {
+			return errors.New("quiet hours need both \"from\" and \"to\", or neither")
+		}
+		if w.From != "" {
+			if _, err := clock(w.From); err != nil { return err }
+			if _, err := clock(w.To); err != nil { return err }
+		}
+		for _, d := range w.Days {
+			if !slices.Contains(weekDays, strings.ToLower(d)) {
+				return fmt.Errorf("invalid day \"%s\", expected one of %s", d, strings.Join(weekDays, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// end returns when the window that contains t ends, if any does.
+func (w Window) end(t time.Time) (time.Time, bool) {
+	t = t.In(time.Local)
+	from, _ := clock(w.From)
+	to, _ := clock(w.To)
+
+	// The window may have started today or, going past midnight, yesterday.
+	for _, offset := range []int{-1, 0} {
+		day := time.Date(t.Year(), t.Month(), t.Day() + offset, 0, 0, 0, 0, time.Local)
+		if len(w.Days) > 0 && !slices.ContainsFunc(w.Days, func(d string) bool {
+			return strings.ToLower(d) == weekDays[day.Weekday()]
+		}) { continue }
+
+		start := day.Add(from)
+		end := day.Add(to)
+		if !end.After(start) { end = time.Date(day.Year(), day.Month(), day.Day() + 1, 0, 0, 0, 0, time.Local).Add(to) }
+
+		if !t.Before(start) && t.Before(end) { return end, true }
+	}
+	return time.Time{}, false
+}
+
+// Contains tells whether t falls in quiet hours.
+func (q QuietHours) Contains(t time.Time) bool {
+	_, ok := q.End(t)
+	return ok
+}
+
+// End returns when the quiet hours t falls in are over, following windows
+// that overlap or touch each other (e.g. a night followed by a weekend).
+func (q QuietHours) End(t time.Time) (time.Time, bool) {
+	quiet := false
+	for range 32 {
+		next := t
+		for _, w := range q {
+			if end, ok := w.end(t); ok && end.After(next) { next = end }
+		}
+		if next.Equal(t) { break }
+		t, quiet = next, true
+	}
+	return t, quiet
+}
+
+// String renders the windows, e.g. "22:00-07:00, sat sun".
+func (q QuietHours) String() string {
+	windows := []string{}
+	for _, w := range q {
+		parts := []string{}
+		if w.From != "" { parts = append(parts, w.From + "-" + w.To) }
+		if len(w.Days) > 0 { parts = append(parts, strings.Join(w.Days, " ")) }
+		if len(parts) == 0 { parts = append(parts, "all day") }
+		windows = append(windows, strings.Join(parts, " on "))
+	}
+	return strings.Join(windows, ", ")
+}
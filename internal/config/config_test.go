@@ -0,0 +1,73 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursEnd(t *testing.T) {
+	// 07/01/2030 is a monday
+	date := func(day int, hour int, minute int) time.Time {
+		return time.Date(2030, 1, day, hour, minute, 0, 0, time.Local)
+	}
+	night := Window{From: "22:00", To: "07:00"}
+	weekend := Window{Days: []string{"sat", "Sun"}}
+
+	tests := []struct {
+		name  string
+		quiet QuietHours
+		at    time.Time
+		want  time.Time
+		ok    bool
+	}{
+		{"before the night", QuietHours{night}, date(7, 21, 59), time.Time{}, false},
+		{"night starts", QuietHours{night}, date(7, 22, 0), date(8, 7, 0), true},
+		{"before midnight", QuietHours{night}, date(7, 23, 30), date(8, 7, 0), true},
+		{"after midnight", QuietHours{night}, date(8, 3, 0), date(8, 7, 0), true},
+		{"night ends", QuietHours{night}, date(8, 7, 0), time.Time{}, false},
+
+		{"same day", QuietHours{{From: "12:00", To: "13:30"}}, date(7, 12, 45), date(7, 13, 30), true},
+		{"after the same day", QuietHours{{From: "12:00", To: "13:30"}}, date(7, 13, 30), time.Time{}, false},
+
+		// days are the ones windows start on
+		{"friday night", QuietHours{{From: "22:00", To: "07:00", Days: []string{"fri"}}}, date(12, 3, 0), date(12, 7, 0), true},
+		{"saturday night", QuietHours{{From: "22:00", To: "07:00", Days: []string{"fri"}}}, date(13, 3, 0), time.Time{}, false},
+
+		{"weekend", QuietHours{weekend}, date(12, 10, 0), date(14, 0, 0), true},
+		{"weekday", QuietHours{weekend}, date(11, 23, 59), time.Time{}, false},
+		// a night followed by the weekend, followed by a night
+		{"friday night and the weekend", QuietHours{night, weekend}, date(11, 23, 0), date(14, 7, 0), true},
+		{"weekend and sunday night", QuietHours{weekend, night}, date(13, 12, 0), date(14, 7, 0), true},
+	}
+
+	for _, tt := range tests {
+		got, ok := tt.quiet.End(tt.at)
+		if ok != tt.ok || (ok && !got.Equal(tt.want)) {
+			t.Errorf("%s: End(%s) = %s, %t, want %s, %t", tt.name, tt.at, got, ok, tt.want, tt.ok)
+		}
+		if contains := tt.quiet.Contains(tt.at); contains != tt.ok {
+			t.Errorf("%s: Contains(%s) = %t, want %t", tt.name, tt.at, contains, tt.ok)
+		}
+	}
+}
+
+func TestQuietHoursCheck(t *testing.T) {
+	tests := []struct {
+		quiet QuietHours
+		ok    bool
+	}{
+		{QuietHours{{From: "22:00", To: "07:00"}}, true},
+		{QuietHours{{Days: []string{"sat", "SUN"}}}, true},
+		{QuietHours{{From: "22:00"}}, false},
+		{QuietHours{{To: "07:00"}}, false},
+		{QuietHours{{From: "22h", To: "07:00"}}, false},
+		{QuietHours{{From: "22:00", To: "24:00"}}, false},
+		{QuietHours{{Days: []string{"saturday"}}}, false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.quiet.check(); (err == nil) != tt.ok {
+			t.Errorf("%v: check() = %v, want ok %t", tt.quiet, err, tt.ok)
+		}
+	}
+}
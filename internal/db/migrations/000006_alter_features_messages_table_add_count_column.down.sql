@@ -1,19 +1,19 @@
-PRAGMA foreign_keys=off;
-
-BEGIN TRANSACTION;
-
-ALTER TABLE messages_features RENAME TO _messages_features_old;
-
-CREATE TABLE messages_features (
+-- SQLite can't drop the column in place, so the table is rebuilt: the new one
+-- is created aside, filled and renamed over the old one. The triggers of other
+-- tables (e.g. list_items) write to messages_features, and the legacy rename
+-- leaves them alone instead of refusing to rename while the table is missing.
+CREATE TABLE messages_features_new (
     message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
     feature_name TEXT NOT NULL REFERENCES features(name) ON DELETE CASCADE,
     PRIMARY KEY (message_id, feature_name)
 );
 
-INSERT INTO messages_features (message_id, feature_name)
+INSERT INTO messages_features_new (message_id, feature_name)
   SELECT message_id, feature_name
-  FROM _messages_features_old;
+  FROM messages_features;
 
-COMMIT;
+DROP TABLE messages_features;
 
-PRAGMA foreign_keys=on;
+PRAGMA legacy_alter_table = ON;
+ALTER TABLE messages_features_new RENAME TO messages_features;
+PRAGMA legacy_alter_table = OFF;
@@ -0,0 +1 @@
+-- Removed orphan rows can't be restored.
@@ -0,0 +1,12 @@
+-- Until now the DSN asked for foreign keys with a parameter the driver
+-- doesn't read, so ON DELETE CASCADE never ran and deleting a message or a
+-- notification left its dependent rows behind. Clean them up once.
+DELETE FROM notifications WHERE message_id NOT IN (SELECT id FROM messages);
+DELETE FROM todos WHERE message_id NOT IN (SELECT id FROM messages);
+
+DELETE FROM simple_notifications WHERE notification_id NOT IN (SELECT id FROM notifications);
+DELETE FROM recurring_notifications WHERE notification_id NOT IN (SELECT id FROM notifications);
+DELETE FROM recurring_notification_days WHERE recurring_notification_id NOT IN (SELECT notification_id FROM recurring_notifications);
+
+DELETE FROM messages_features WHERE message_id NOT IN (SELECT id FROM messages);
+DELETE FROM messages_features WHERE feature_name NOT IN (SELECT name FROM features);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS notifications_messages_features_insert;
+DROP TRIGGER IF EXISTS notifications_messages_features_delete;
+DROP TRIGGER IF EXISTS todos_messages_features_insert;
+DROP TRIGGER IF EXISTS todos_messages_features_delete;
@@ -0,0 +1,44 @@
+-- messages_features.count is kept in sync by the database: every feature
+-- table gets an insert and a delete trigger like the ones below, and rows
+-- are removed once their count reaches zero.
+CREATE TRIGGER notifications_messages_features_insert AFTER INSERT ON notifications
+BEGIN
+    INSERT INTO messages_features (message_id, feature_name, count) VALUES (NEW.message_id, 'notifications', 1)
+    ON CONFLICT (message_id, feature_name) DO UPDATE SET count = count + 1;
+END;
+
+CREATE TRIGGER notifications_messages_features_delete AFTER DELETE ON notifications
+BEGIN
+    UPDATE messages_features SET count = count - 1
+    WHERE message_id = OLD.message_id AND feature_name = 'notifications';
+    DELETE FROM messages_features
+    WHERE message_id = OLD.message_id AND feature_name = 'notifications' AND count <= 0;
+END;
+
+CREATE TRIGGER todos_messages_features_insert AFTER INSERT ON todos
+BEGIN
+    INSERT INTO messages_features (message_id, feature_name, count) VALUES (NEW.message_id, 'todos', 1)
+    ON CONFLICT (message_id, feature_name) DO UPDATE SET count = count + 1;
+END;
+
+CREATE TRIGGER todos_messages_features_delete AFTER DELETE ON todos
+BEGIN
+    UPDATE messages_features SET count = count - 1
+    WHERE message_id = OLD.message_id AND feature_name = 'todos';
+    DELETE FROM messages_features
+    WHERE message_id = OLD.message_id AND feature_name = 'todos' AND count <= 0;
+END;
+
+-- Counts maintained by hand may have drifted, rebuild them from the feature
+-- tables.
+DELETE FROM messages_features WHERE feature_name IN ('notifications', 'todos');
+
+INSERT INTO messages_features (message_id, feature_name, count)
+  SELECT message_id, 'notifications', COUNT(*)
+  FROM notifications
+  GROUP BY message_id;
+
+INSERT INTO messages_features (message_id, feature_name, count)
+  SELECT message_id, 'todos', COUNT(*)
+  FROM todos
+  GROUP BY message_id;
@@ -0,0 +1,5 @@
+DELETE FROM notifications WHERE type = 'multi';
+DELETE FROM type_enum WHERE type = 'multi';
+
+DROP TABLE IF EXISTS multi_notification_dates;
+DROP TABLE IF EXISTS multi_notifications;
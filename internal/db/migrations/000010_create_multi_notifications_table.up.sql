@@ -0,0 +1,11 @@
+CREATE TABLE multi_notifications (
+    notification_id INTEGER PRIMARY KEY REFERENCES notifications(id) ON DELETE CASCADE
+);
+
+CREATE TABLE multi_notification_dates (
+    multi_notification_id INTEGER NOT NULL REFERENCES multi_notifications(notification_id) ON DELETE CASCADE,
+    trigger_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (multi_notification_id, trigger_at)
+);
+
+INSERT INTO type_enum (type, seq) VALUES ('multi', 3);
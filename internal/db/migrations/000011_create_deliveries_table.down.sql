@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS deliveries_occurrence_at_idx;
+DROP TABLE IF EXISTS deliveries;
+DROP TABLE IF EXISTS outcome_enum;
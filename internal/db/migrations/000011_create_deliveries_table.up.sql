@@ -0,0 +1,22 @@
+CREATE TABLE outcome_enum (
+    name TEXT PRIMARY KEY,
+    seq INTEGER
+);
+
+CREATE TABLE deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    notification_id INTEGER NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    occurrence_at TIMESTAMP NOT NULL,
+    outcome TEXT NOT NULL DEFAULT ('pending') REFERENCES outcome_enum(name) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    error TEXT,
+    claimed_at TIMESTAMP NOT NULL,
+    delivered_at TIMESTAMP,
+    UNIQUE (notification_id, occurrence_at)
+);
+
+CREATE INDEX deliveries_occurrence_at_idx ON deliveries (occurrence_at);
+
+INSERT INTO outcome_enum (name, seq) VALUES ('pending', 1);
+INSERT INTO outcome_enum (name, seq) VALUES ('delivered', 2);
+INSERT INTO outcome_enum (name, seq) VALUES ('failed', 3);
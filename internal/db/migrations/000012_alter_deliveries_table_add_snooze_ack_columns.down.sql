@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS deliveries_snoozed_until_idx;
+
+ALTER TABLE deliveries DROP COLUMN acked_at;
+ALTER TABLE deliveries DROP COLUMN snoozed_until;
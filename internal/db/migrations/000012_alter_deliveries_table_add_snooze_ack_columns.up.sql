@@ -0,0 +1,4 @@
+ALTER TABLE deliveries ADD COLUMN snoozed_until TIMESTAMP;
+ALTER TABLE deliveries ADD COLUMN acked_at TIMESTAMP;
+
+CREATE INDEX deliveries_snoozed_until_idx ON deliveries (snoozed_until);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS notifications_notification_settings_insert;
+
+DROP TABLE IF EXISTS notification_settings;
+DROP TABLE IF EXISTS catch_up_enum;
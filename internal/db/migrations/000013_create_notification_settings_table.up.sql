@@ -0,0 +1,24 @@
+CREATE TABLE catch_up_enum (
+    name TEXT PRIMARY KEY,
+    seq INTEGER
+);
+
+-- Every notification gets a settings row, created by the trigger below so
+-- the code creating notifications doesn't have to care about it.
+CREATE TABLE notification_settings (
+    notification_id INTEGER PRIMARY KEY REFERENCES notifications(id) ON DELETE CASCADE,
+    catch_up TEXT NOT NULL DEFAULT ('latest') REFERENCES catch_up_enum(name) ON DELETE CASCADE,
+    -- in seconds, NULL means missed occurrences are never too late
+    max_lateness INTEGER CHECK (max_lateness > 0)
+);
+
+INSERT INTO catch_up_enum (name, seq) VALUES ('all', 1);
+INSERT INTO catch_up_enum (name, seq) VALUES ('latest', 2);
+INSERT INTO catch_up_enum (name, seq) VALUES ('skip', 3);
+
+CREATE TRIGGER notifications_notification_settings_insert AFTER INSERT ON notifications
+BEGIN
+    INSERT INTO notification_settings (notification_id) VALUES (NEW.id);
+END;
+
+INSERT INTO notification_settings (notification_id) SELECT id FROM notifications;
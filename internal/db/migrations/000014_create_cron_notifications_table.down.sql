@@ -0,0 +1,4 @@
+DELETE FROM notifications WHERE type = 'cron';
+DELETE FROM type_enum WHERE type = 'cron';
+
+DROP TABLE IF EXISTS cron_notifications;
@@ -0,0 +1,6 @@
+CREATE TABLE cron_notifications (
+    notification_id INTEGER PRIMARY KEY REFERENCES notifications(id) ON DELETE CASCADE,
+    expression TEXT NOT NULL
+);
+
+INSERT INTO type_enum (type, seq) VALUES ('cron', 4);
@@ -0,0 +1,5 @@
+DELETE FROM notifications WHERE type = 'interval';
+DELETE FROM type_enum WHERE type = 'interval';
+
+DROP TABLE IF EXISTS interval_notifications;
+DROP TABLE IF EXISTS interval_unit_enum;
@@ -0,0 +1,22 @@
+CREATE TABLE interval_unit_enum (
+    name TEXT PRIMARY KEY,
+    seq INTEGER
+);
+
+-- Triggers every `every` units starting at anchor, which also gives the time
+-- of the day and, depending on the unit, the weekday, day of month or date.
+CREATE TABLE interval_notifications (
+    notification_id INTEGER PRIMARY KEY REFERENCES notifications(id) ON DELETE CASCADE,
+    every INTEGER NOT NULL CHECK (every > 0),
+    unit TEXT NOT NULL REFERENCES interval_unit_enum(name) ON DELETE CASCADE,
+    anchor TIMESTAMP NOT NULL,
+    -- monthly only: trigger on the last day of the month instead of the anchor's day
+    last_day BOOLEAN NOT NULL DEFAULT false
+);
+
+INSERT INTO interval_unit_enum (name, seq) VALUES ('day', 1);
+INSERT INTO interval_unit_enum (name, seq) VALUES ('week', 2);
+INSERT INTO interval_unit_enum (name, seq) VALUES ('month', 3);
+INSERT INTO interval_unit_enum (name, seq) VALUES ('year', 4);
+
+INSERT INTO type_enum (type, seq) VALUES ('interval', 5);
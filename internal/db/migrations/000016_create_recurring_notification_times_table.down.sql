@@ -0,0 +1,9 @@
+ALTER TABLE recurring_notifications ADD COLUMN trigger_at_time TEXT CHECK(trigger_at_time GLOB '??-??-??');
+
+-- Only the earliest time of each notification fits in the old column.
+UPDATE recurring_notifications SET trigger_at_time = (
+    SELECT MIN(trigger_at_time) FROM recurring_notification_times
+    WHERE recurring_notification_id = recurring_notifications.notification_id
+);
+
+DROP TABLE IF EXISTS recurring_notification_times;
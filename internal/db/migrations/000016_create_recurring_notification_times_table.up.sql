@@ -0,0 +1,11 @@
+CREATE TABLE recurring_notification_times (
+    recurring_notification_id INTEGER NOT NULL REFERENCES recurring_notifications(notification_id) ON DELETE CASCADE,
+    trigger_at_time TEXT NOT NULL CHECK(trigger_at_time GLOB '??-??-??'),
+    PRIMARY KEY (recurring_notification_id, trigger_at_time)
+);
+
+INSERT INTO recurring_notification_times (recurring_notification_id, trigger_at_time)
+SELECT notification_id, trigger_at_time FROM recurring_notifications
+WHERE trigger_at_time IS NOT NULL;
+
+ALTER TABLE recurring_notifications DROP COLUMN trigger_at_time;
@@ -0,0 +1,5 @@
+ALTER TABLE notifications DROP COLUMN finished_at;
+
+ALTER TABLE notification_settings DROP COLUMN max_occurrences;
+ALTER TABLE notification_settings DROP COLUMN ends_at;
+ALTER TABLE notification_settings DROP COLUMN starts_at;
@@ -0,0 +1,8 @@
+-- Optional bounds of a notification's occurrences. NULL means unbounded.
+ALTER TABLE notification_settings ADD COLUMN starts_at TIMESTAMP;
+ALTER TABLE notification_settings ADD COLUMN ends_at TIMESTAMP;
+ALTER TABLE notification_settings ADD COLUMN max_occurrences INTEGER CHECK (max_occurrences > 0);
+
+-- Set once a notification is past its bounds. Finished notifications are
+-- kept, along with their deliveries, but never trigger again.
+ALTER TABLE notifications ADD COLUMN finished_at TIMESTAMP;
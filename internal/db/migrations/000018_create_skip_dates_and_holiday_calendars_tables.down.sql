@@ -0,0 +1,6 @@
+ALTER TABLE notification_settings DROP COLUMN shift_skipped;
+ALTER TABLE notification_settings DROP COLUMN calendar;
+
+DROP TABLE IF EXISTS holiday_calendar_dates;
+DROP TABLE IF EXISTS holiday_calendars;
+DROP TABLE IF EXISTS notification_skip_dates;
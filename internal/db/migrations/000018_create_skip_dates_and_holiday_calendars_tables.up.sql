@@ -0,0 +1,22 @@
+-- Dates are local calendar days, stored as YYYY-MM-DD.
+CREATE TABLE notification_skip_dates (
+    notification_id INTEGER NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    date TEXT NOT NULL CHECK(date GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]'),
+    PRIMARY KEY (notification_id, date)
+);
+
+CREATE TABLE holiday_calendars (
+    name TEXT PRIMARY KEY NOT NULL
+);
+
+CREATE TABLE holiday_calendar_dates (
+    calendar TEXT NOT NULL REFERENCES holiday_calendars(name) ON DELETE CASCADE,
+    date TEXT NOT NULL CHECK(date GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]'),
+    description TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (calendar, date)
+);
+
+ALTER TABLE notification_settings ADD COLUMN calendar TEXT REFERENCES holiday_calendars(name) ON DELETE SET NULL;
+-- Whether occurrences on skipped dates move to the next business day instead
+-- of being dropped.
+ALTER TABLE notification_settings ADD COLUMN shift_skipped BOOLEAN NOT NULL DEFAULT false;
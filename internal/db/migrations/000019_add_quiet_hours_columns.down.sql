@@ -0,0 +1,5 @@
+ALTER TABLE notification_settings DROP COLUMN urgent;
+
+-- Held deliveries become failed ones, so they're still retried.
+UPDATE deliveries SET outcome = 'failed', error = 'held back during quiet hours' WHERE outcome = 'held';
+DELETE FROM outcome_enum WHERE name = 'held';
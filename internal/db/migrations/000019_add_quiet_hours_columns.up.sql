@@ -0,0 +1,5 @@
+-- Occurrences due during quiet hours are held back and sent once they end.
+INSERT INTO outcome_enum (name, seq) VALUES ('held', 4);
+
+-- Urgent notifications are sent even during quiet hours.
+ALTER TABLE notification_settings ADD COLUMN urgent BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,6 @@
+ALTER TABLE deliveries DROP COLUMN next_repeat_at;
+ALTER TABLE deliveries DROP COLUMN repeats;
+
+ALTER TABLE notification_settings DROP COLUMN escalate_to;
+ALTER TABLE notification_settings DROP COLUMN max_repeats;
+ALTER TABLE notification_settings DROP COLUMN repeat_every;
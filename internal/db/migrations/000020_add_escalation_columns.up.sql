@@ -0,0 +1,10 @@
+-- Delivered occurrences of notifications with repeat_every set (in seconds)
+-- are sent again until acknowledged, at most max_repeats times. Repeats also
+-- go to the escalate_to sink when there's one.
+ALTER TABLE notification_settings ADD COLUMN repeat_every INTEGER CHECK (repeat_every > 0);
+ALTER TABLE notification_settings ADD COLUMN max_repeats INTEGER CHECK (max_repeats > 0);
+ALTER TABLE notification_settings ADD COLUMN escalate_to TEXT;
+
+-- How many times the occurrence was repeated and when the next repeat is due.
+ALTER TABLE deliveries ADD COLUMN repeats INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE deliveries ADD COLUMN next_repeat_at TIMESTAMP;
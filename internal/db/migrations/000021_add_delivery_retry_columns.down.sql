@@ -0,0 +1,6 @@
+-- Deliveries that were given up on become failed ones again.
+UPDATE deliveries SET outcome = 'failed' WHERE outcome = 'gave_up';
+DELETE FROM outcome_enum WHERE name = 'gave_up';
+
+DROP INDEX deliveries_retry_at_idx;
+ALTER TABLE deliveries DROP COLUMN retry_at;
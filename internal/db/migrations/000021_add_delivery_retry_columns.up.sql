@@ -0,0 +1,7 @@
+-- Failed deliveries are retried once retry_at has passed, or on the next run
+-- when it's NULL. Sinks that space out their retries give up after a while.
+ALTER TABLE deliveries ADD COLUMN retry_at TIMESTAMP;
+
+CREATE INDEX deliveries_retry_at_idx ON deliveries (retry_at);
+
+INSERT INTO outcome_enum (name, seq) VALUES ('gave_up', 5);
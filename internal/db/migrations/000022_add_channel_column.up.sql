@@ -0,0 +1,3 @@
+-- Name of the channel (see "channels" in the config file) the notification is
+-- sent to. NULL routes it by type or to the default channel.
+ALTER TABLE notification_settings ADD COLUMN channel TEXT;
@@ -0,0 +1,3 @@
+-- text/template the notification is rendered with, over the channel's and
+-- the global one. NULL renders it with those.
+ALTER TABLE notification_settings ADD COLUMN template TEXT;
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS deliveries_delivery_sinks_delivered;
+DROP TABLE IF EXISTS delivery_sinks;
@@ -0,0 +1,14 @@
+-- The sinks of its channel a delivery already went out to, so retrying it
+-- only sends to the ones that failed. The rows go away once it's delivered.
+CREATE TABLE delivery_sinks (
+    delivery_id INTEGER NOT NULL REFERENCES deliveries(id) ON DELETE CASCADE,
+    sink TEXT NOT NULL,
+    sent_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (delivery_id, sink)
+);
+
+CREATE TRIGGER deliveries_delivery_sinks_delivered AFTER UPDATE OF outcome ON deliveries
+WHEN NEW.outcome = 'delivered'
+BEGIN
+    DELETE FROM delivery_sinks WHERE delivery_id = NEW.id;
+END;
@@ -0,0 +1,278 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Core holds the migrations shipped with gmess itself.
+var Core = Source{Name: "core", FS: files}
+
+// Source is a named set of migrations. Each source keeps its own version
+// in the schema_version table.
+type Source struct {
+	Name string
+	FS   fs.FS
+}
+
+var sources = []Source{Core}
+
+// Register adds a source whose migrations are applied after the core ones.
+func Register(src Source) {
+	if slices.ContainsFunc(sources, func(s Source) bool { return s.Name == src.Name }) {
+		panic("migrations: source \"" + src.Name + "\" registered twice")
+	}
+	sources = append(sources, src)
+}
+
+// Sources returns the core source followed by every registered one.
+func Sources() []Source {
+	return slices.Clone(sources)
+}
+
+// GetSource looks up a source by name.
+func GetSource(name string) (Source, bool) {
+	i := slices.IndexFunc(sources, func(s Source) bool { return s.Name == name })
+	if i == -1 { return Source{}, false }
+	return sources[i], true
+}
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Source     string
+	Version    int64
+	Migrations []Migration
+}
+
+const schemaVersionDDL = `CREATE TABLE IF NOT EXISTS schema_version (
+    source TEXT PRIMARY KEY NOT NULL,
+    version INTEGER NOT NULL
+)`
+
+// Databases created before the runner existed were migrated by hand with
+// `make migrate`, so they have no schema_version row. The probes below are
+// tried in order and the first one that matches tells which core migration
+// was the last one applied.
+var legacyProbes = []struct {
+	version int64
+	query   string
+}{
+	{7, `SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'todos'`},
+	{6, `SELECT 1 FROM pragma_table_info('messages_features') WHERE name = 'count'`},
+	{5, `SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'recurring_notifications'`},
+	{4, `SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'simple_notifications'`},
+	{3, `SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'notifications'`},
+	{2, `SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'features'`},
+	{1, `SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'messages'`},
+}
+
+// Load reads every "NNNNNN_name.up.sql"/"NNNNNN_name.down.sql" pair in the
+// source, sorted by version.
+func Load(src Source) ([]Migration, error) {
+	entries, err := fs.ReadDir(src.FS, ".")
+	if err != nil { return nil, err }
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" { continue }
+
+		base := strings.TrimSuffix(entry.Name(), ".sql")
+		direction := path.Ext(base)
+		if direction != ".up" && direction != ".down" {
+			return nil, fmt.Errorf("%s: invalid migration file name \"%s\"", src.Name, entry.Name())
+		}
+		base = strings.TrimSuffix(base, direction)
+
+		prefix, name, found := strings.Cut(base, "_")
+		if !found {
+			return nil, fmt.Errorf("%s: invalid migration file name \"%s\"", src.Name, entry.Name())
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid migration version \"%s\"", src.Name, entry.Name())
+		}
+
+		content, err := fs.ReadFile(src.FS, entry.Name())
+		if err != nil { return nil, err }
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if direction == ".up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := []Migration{}
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return int(a.Version - b.Version) })
+
+	return migrations, nil
+}
+
+// Up applies every pending migration of the source.
+func Up(ctx context.Context, db *sql.DB, src Source) error {
+	migrations, err := Load(src)
+	if err != nil { return err }
+	if len(migrations) == 0 { return nil }
+
+	return migrate(ctx, db, src, migrations, migrations[len(migrations)-1].Version)
+}
+
+// Down reverts the last applied migration of the source.
+func Down(ctx context.Context, db *sql.DB, src Source) error {
+	migrations, err := Load(src)
+	if err != nil { return err }
+
+	conn, err := db.Conn(ctx)
+	if err != nil { return err }
+	current, err := version(ctx, conn, src)
+	conn.Close()
+	if err != nil { return err }
+	if current == 0 { return errors.New("no migration to revert") }
+
+	target := int64(0)
+	for _, m := range migrations {
+		if m.Version < current { target = m.Version }
+	}
+
+	return migrate(ctx, db, src, migrations, target)
+}
+
+// Goto migrates the source up or down until it reaches the given version.
+func Goto(ctx context.Context, db *sql.DB, src Source, target int64) error {
+	migrations, err := Load(src)
+	if err != nil { return err }
+
+	if target != 0 && !slices.ContainsFunc(migrations, func(m Migration) bool { return m.Version == target }) {
+		return fmt.Errorf("%s: migration %d does not exist", src.Name, target)
+	}
+
+	return migrate(ctx, db, src, migrations, target)
+}
+
+// GetStatus reports the current version of the source together with all the
+// migrations it knows about.
+func GetStatus(ctx context.Context, db *sql.DB, src Source) (Status, error) {
+	migrations, err := Load(src)
+	if err != nil { return Status{}, err }
+
+	conn, err := db.Conn(ctx)
+	if err != nil { return Status{}, err }
+	defer conn.Close()
+
+	current, err := version(ctx, conn, src)
+	if err != nil { return Status{}, err }
+
+	return Status{Source: src.Name, Version: current, Migrations: migrations}, nil
+}
+
+func migrate(ctx context.Context, db *sql.DB, src Source, migrations []Migration, target int64) error {
+	// PRAGMA foreign_keys is per connection and can't be changed inside a
+	// transaction, so every step runs on the same pinned connection with the
+	// checks turned off, allowing migrations to rebuild tables.
+	conn, err := db.Conn(ctx)
+	if err != nil { return err }
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil { return err }
+	defer conn.ExecContext(context.Background(), "PRAGMA foreign_keys = ON")
+
+	current, err := version(ctx, conn, src)
+	if err != nil { return err }
+
+	if target >= current {
+		for _, m := range migrations {
+			if m.Version <= current || m.Version > target { continue }
+			if err := step(ctx, conn, src, m.Up, m.Version); err != nil {
+				return fmt.Errorf("%s: migration %06d_%s up: %w", src.Name, m.Version, m.Name, err)
+			}
+		}
+		return nil
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.Version > current || m.Version <= target { continue }
+
+		previous := int64(0)
+		if i > 0 { previous = migrations[i-1].Version }
+
+		if err := step(ctx, conn, src, m.Down, previous); err != nil {
+			return fmt.Errorf("%s: migration %06d_%s down: %w", src.Name, m.Version, m.Name, err)
+		}
+	}
+	return nil
+}
+
+func step(ctx context.Context, conn *sql.Conn, src Source, script string, newVersion int64) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil { return err }
+
+	if strings.TrimSpace(script) != "" {
+		if _, err := tx.ExecContext(ctx, script); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO schema_version (source, version) VALUES (?, ?)
+		ON CONFLICT (source) DO UPDATE SET version = excluded.version`,
+		src.Name, newVersion,
+	); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func version(ctx context.Context, conn *sql.Conn, src Source) (int64, error) {
+	if _, err := conn.ExecContext(ctx, schemaVersionDDL); err != nil { return 0, err }
+
+	var current int64
+	err := conn.QueryRowContext(ctx, "SELECT version FROM schema_version WHERE source = ?", src.Name).Scan(&current)
+	if err == nil { return current, nil }
+	if !errors.Is(err, sql.ErrNoRows) { return 0, err }
+
+	if src.Name != Core.Name { return 0, nil }
+
+	for _, probe := range legacyProbes {
+		var found int64
+		err := conn.QueryRowContext(ctx, probe.query).Scan(&found)
+		if errors.Is(err, sql.ErrNoRows) { continue }
+		if err != nil { return 0, err }
+
+		if _, err := conn.ExecContext(ctx,
+			"INSERT INTO schema_version (source, version) VALUES (?, ?)",
+			src.Name, probe.version,
+		); err != nil { return 0, err }
+		return probe.version, nil
+	}
+
+	return 0, nil
+}
@@ -4,28 +4,10 @@ SELECT * FROM features;
 -- name: GetFeaturesByMessageId :many
 SELECT feature_name, count FROM messages_features WHERE message_id = ?;
 
--- name: GetPrettyFeaturesByMessageId :one
-SELECT GROUP_CONCAT(SUBSTR(feature_name, 1, 3), ', ')
-FROM messages_features 
-WHERE message_id = ? AND count > 0;
-
--- name: GetMessageAndFeatures :one
-SELECT 
-  messages.*,
-  GROUP_CONCAT(messages_features.feature_name, ', ') AS features
-FROM messages
-INNER JOIN messages_features ON messages_features.message_id = messages.id
-WHERE messages.id = ? AND messages_features.count > 0
-GROUP BY messages.id;
-
--- name: CreateMessageFeature :exec
-INSERT INTO messages_features (message_id, feature_name) VALUES (?, ?);
-
--- name: IncrementMessageFeatureCount :exec
-UPDATE messages_features SET count = count + 1 WHERE message_id = ? AND feature_name = ?;
-
--- name: DecrementMessageFeatureCount :exec
-UPDATE messages_features SET count = count - 1 WHERE message_id = ? AND feature_name = ?;
+-- name: CreateFeatureIfNotExists :exec
+INSERT INTO features (name, seq)
+SELECT sqlc.arg(name), COALESCE(MAX(seq), 0) + 1 FROM features WHERE true
+ON CONFLICT (name) DO NOTHING;
 
 -- name: FeatureExists :one
 SELECT EXISTS(
@@ -19,6 +19,9 @@ SELECT * FROM notifications ORDER BY type ASC;
 -- name: GetNotificationsOrderByTypeDESC :many
 SELECT * FROM notifications ORDER BY type DESC;
 
+-- name: GetNotificationsByMessageId :many
+SELECT * FROM notifications WHERE message_id = ?;
+
 -- name: GetNotificationAndMessageById :one
 SELECT 
     sqlc.embed(notifications),
@@ -46,3 +49,15 @@ DELETE FROM notifications WHERE id = ?;
 
 -- name: DeleteNotificationByIdReturningMsgId :one
 DELETE FROM notifications WHERE id = ? RETURNING message_id;
+
+-- name: GetUnfinishedNotifications :many
+SELECT * FROM notifications WHERE finished_at IS NULL;
+
+-- name: FinishNotification :exec
+UPDATE notifications SET finished_at = ? WHERE id = ?;
+
+-- name: UnfinishNotification :exec
+UPDATE notifications SET finished_at = NULL WHERE id = ?;
+
+-- name: CountNotificationsByMessage :many
+SELECT message_id, COUNT(*) AS count FROM notifications GROUP BY message_id;
@@ -4,8 +4,8 @@ SELECT * FROM recurring_notifications WHERE notification_id = ?;
 -- name: GetRecurringNotificationDaysByNotificationId :many
 SELECT * FROM recurring_notification_days WHERE recurring_notification_id = ?;
 
--- name: CreateRecurringNotification :one
-INSERT INTO recurring_notifications (notification_id, trigger_at_time) VALUES (?, ?) RETURNING *;
+-- name: CreateRecurringNotification :exec
+INSERT INTO recurring_notifications (notification_id) VALUES (?);
 
 -- name: CreateRecurringNotificationDay :exec
 INSERT INTO recurring_notification_days (recurring_notification_id, week_day) VALUES (?, ?);
@@ -17,8 +17,5 @@ SELECT EXISTS(
     AND week_day = ?
 ) AS "exists";
 
--- name: UpdateRecurringNotification :one
-UPDATE recurring_notifications SET trigger_at_time = ? WHERE notification_id = ? RETURNING *;
-
 -- name: DeleteRecurringNotificationDayByNotificationId :exec
 DELETE FROM recurring_notification_days WHERE recurring_notification_id = ? AND week_day = ?;
@@ -57,3 +57,6 @@ DELETE FROM todos WHERE id = ? RETURNING message_id;
 
 -- name: DeleteTodoByMessageId :exec
 DELETE FROM todos WHERE message_id = ?;
+
+-- name: CountTodosByMessage :many
+SELECT message_id, COUNT(*) AS count FROM todos GROUP BY message_id;
@@ -0,0 +1,65 @@
+-- name: GetPositiveMessageFeatures :many
+SELECT * FROM messages_features WHERE count > 0;
+
+-- name: GetNonPositiveMessageFeatures :many
+SELECT * FROM messages_features WHERE count <= 0;
+
+-- name: SetMessageFeatureCount :exec
+INSERT INTO messages_features (message_id, feature_name, count) VALUES (?, ?, ?)
+ON CONFLICT (message_id, feature_name) DO UPDATE SET count = excluded.count;
+
+-- name: DeleteMessageFeature :exec
+DELETE FROM messages_features WHERE message_id = ? AND feature_name = ?;
+
+-- name: GetOrphanSimpleNotifications :many
+SELECT * FROM simple_notifications
+WHERE notification_id NOT IN (
+    SELECT id FROM notifications WHERE type = 'simple'
+);
+
+-- name: DeleteSimpleNotification :exec
+DELETE FROM simple_notifications WHERE notification_id = ?;
+
+-- name: GetOrphanRecurringNotifications :many
+SELECT * FROM recurring_notifications
+WHERE notification_id NOT IN (
+    SELECT id FROM notifications WHERE type = 'recurring'
+);
+
+-- name: DeleteRecurringNotification :exec
+DELETE FROM recurring_notifications WHERE notification_id = ?;
+
+-- name: GetOrphanMultiNotifications :many
+SELECT * FROM multi_notifications
+WHERE notification_id NOT IN (
+    SELECT id FROM notifications WHERE type = 'multi'
+);
+
+-- name: DeleteMultiNotification :exec
+DELETE FROM multi_notifications WHERE notification_id = ?;
+
+-- name: GetOrphanCronNotifications :many
+SELECT * FROM cron_notifications
+WHERE notification_id NOT IN (
+    SELECT id FROM notifications WHERE type = 'cron'
+);
+
+-- name: DeleteCronNotification :exec
+DELETE FROM cron_notifications WHERE notification_id = ?;
+
+-- name: GetOrphanIntervalNotifications :many
+SELECT notification_id FROM interval_notifications
+WHERE notification_id NOT IN (
+    SELECT id FROM notifications WHERE type = 'interval'
+);
+
+-- name: DeleteIntervalNotification :exec
+DELETE FROM interval_notifications WHERE notification_id = ?;
+
+-- name: GetNotificationsWithoutDetails :many
+SELECT * FROM notifications
+WHERE (type = 'simple' AND id NOT IN (SELECT notification_id FROM simple_notifications))
+OR (type = 'recurring' AND id NOT IN (SELECT recurring_notification_id FROM recurring_notification_times))
+OR (type = 'multi' AND id NOT IN (SELECT multi_notification_id FROM multi_notification_dates))
+OR (type = 'cron' AND id NOT IN (SELECT notification_id FROM cron_notifications))
+OR (type = 'interval' AND id NOT IN (SELECT notification_id FROM interval_notifications));
@@ -0,0 +1,24 @@
+-- name: CreateMultiNotification :exec
+INSERT INTO multi_notifications (notification_id) VALUES (?);
+
+-- name: GetMultiNotificationDatesByNotificationId :many
+SELECT * FROM multi_notification_dates WHERE multi_notification_id = ? ORDER BY trigger_at ASC;
+
+-- name: CreateMultiNotificationDate :exec
+INSERT INTO multi_notification_dates (multi_notification_id, trigger_at) VALUES (?, ?);
+
+-- name: MultiNotificationHasDate :one
+SELECT EXISTS(
+    SELECT 1 FROM multi_notification_dates
+    WHERE multi_notification_id = ?
+    AND trigger_at = ?
+) AS "exists";
+
+-- name: CountMultiNotificationDates :one
+SELECT COUNT(*) FROM multi_notification_dates WHERE multi_notification_id = ?;
+
+-- name: DeleteMultiNotificationDate :exec
+DELETE FROM multi_notification_dates WHERE multi_notification_id = ? AND trigger_at = ?;
+
+-- name: DeleteMultiNotificationDatesByNotificationId :exec
+DELETE FROM multi_notification_dates WHERE multi_notification_id = ?;
@@ -0,0 +1,167 @@
+-- name: ClaimDelivery :one
+INSERT INTO deliveries (notification_id, occurrence_at, outcome, claimed_at)
+VALUES (?, ?, 'pending', ?)
+ON CONFLICT (notification_id, occurrence_at) DO UPDATE
+SET outcome = 'pending', attempts = deliveries.attempts + 1, claimed_at = excluded.claimed_at, retry_at = NULL
+WHERE deliveries.acked_at IS NULL
+AND (
+    (deliveries.outcome = 'failed' AND (deliveries.retry_at IS NULL OR deliveries.retry_at <= excluded.claimed_at))
+    OR (deliveries.outcome = 'pending' AND deliveries.claimed_at < sqlc.arg(stale_before))
+)
+RETURNING *;
+
+-- name: SetDeliveryDelivered :exec
+UPDATE deliveries SET outcome = 'delivered', error = NULL, delivered_at = ? WHERE id = ?;
+
+-- name: SetDeliveryFailed :exec
+UPDATE deliveries SET outcome = 'failed', error = ?, retry_at = ? WHERE id = ?;
+
+-- name: SetDeliveryGaveUp :exec
+UPDATE deliveries SET outcome = 'gave_up', error = ?, retry_at = NULL WHERE id = ?;
+
+-- name: GetDeliveriesAndMessages :many
+SELECT
+    sqlc.embed(deliveries),
+    sqlc.embed(notifications),
+    sqlc.embed(messages)
+FROM deliveries
+INNER JOIN notifications ON notifications.id = deliveries.notification_id
+INNER JOIN messages ON messages.id = notifications.message_id
+ORDER BY deliveries.occurrence_at DESC, deliveries.id DESC;
+
+-- name: GetDeliveriesAndMessagesByNotificationId :many
+SELECT
+    sqlc.embed(deliveries),
+    sqlc.embed(notifications),
+    sqlc.embed(messages)
+FROM deliveries
+INNER JOIN notifications ON notifications.id = deliveries.notification_id
+INNER JOIN messages ON messages.id = notifications.message_id
+WHERE deliveries.notification_id = ?
+ORDER BY deliveries.occurrence_at DESC, deliveries.id DESC;
+
+-- name: GetUnackedDeliveriesAndMessages :many
+SELECT
+    sqlc.embed(deliveries),
+    sqlc.embed(notifications),
+    sqlc.embed(messages)
+FROM deliveries
+INNER JOIN notifications ON notifications.id = deliveries.notification_id
+INNER JOIN messages ON messages.id = notifications.message_id
+WHERE deliveries.acked_at IS NULL
+AND (deliveries.outcome != 'delivered' OR deliveries.snoozed_until IS NOT NULL OR deliveries.next_repeat_at IS NOT NULL)
+ORDER BY deliveries.occurrence_at ASC, deliveries.id ASC;
+
+-- name: GetCurrentDelivery :one
+SELECT * FROM deliveries
+WHERE notification_id = ?
+AND outcome != 'pending'
+AND acked_at IS NULL
+ORDER BY occurrence_at DESC
+LIMIT 1;
+
+-- name: SnoozeDelivery :exec
+UPDATE deliveries SET snoozed_until = ? WHERE id = ?;
+
+-- name: AckDelivery :exec
+UPDATE deliveries SET acked_at = ?, snoozed_until = NULL WHERE id = ?;
+
+-- name: GetDueSnoozedDeliveries :many
+SELECT * FROM deliveries
+WHERE acked_at IS NULL
+AND snoozed_until <= ?
+ORDER BY snoozed_until ASC;
+
+-- name: ClaimSnoozedDelivery :one
+UPDATE deliveries SET snoozed_until = NULL, outcome = 'pending', attempts = 1, claimed_at = ?, retry_at = NULL
+WHERE id = ?
+AND acked_at IS NULL
+AND snoozed_until <= ?
+RETURNING *;
+
+-- name: GetNextSnoozedUntil :one
+SELECT snoozed_until FROM deliveries
+WHERE acked_at IS NULL
+AND snoozed_until IS NOT NULL
+ORDER BY snoozed_until ASC
+LIMIT 1;
+
+-- name: GetLastOccurrenceAt :one
+SELECT occurrence_at FROM deliveries
+WHERE notification_id = ?
+ORDER BY occurrence_at DESC
+LIMIT 1;
+
+-- name: GetRetryableDeliveries :many
+SELECT * FROM deliveries
+WHERE acked_at IS NULL
+AND (
+    (outcome = 'failed' AND (retry_at IS NULL OR retry_at <= sqlc.arg(retry_before)))
+    OR (outcome = 'pending' AND claimed_at < sqlc.arg(stale_before))
+)
+ORDER BY occurrence_at ASC;
+
+-- name: CountDeliveriesByNotificationId :one
+SELECT COUNT(*) FROM deliveries WHERE notification_id = ?;
+
+-- name: SetDeliveryHeld :exec
+UPDATE deliveries SET outcome = 'held' WHERE id = ?;
+
+-- name: GetHeldDeliveries :many
+SELECT * FROM deliveries
+WHERE outcome = 'held'
+AND acked_at IS NULL
+ORDER BY occurrence_at ASC;
+
+-- name: ClaimHeldDelivery :execrows
+UPDATE deliveries SET outcome = 'pending', claimed_at = ?
+WHERE id = ?
+AND outcome = 'held'
+AND acked_at IS NULL;
+
+-- name: ScheduleRepeat :exec
+UPDATE deliveries SET next_repeat_at = ? WHERE id = ?;
+
+-- name: GetDueRepeats :many
+SELECT * FROM deliveries
+WHERE acked_at IS NULL
+AND snoozed_until IS NULL
+AND next_repeat_at <= ?
+ORDER BY next_repeat_at ASC;
+
+-- name: ClaimRepeat :execrows
+UPDATE deliveries SET repeats = repeats + 1, next_repeat_at = NULL
+WHERE id = ?
+AND acked_at IS NULL
+AND snoozed_until IS NULL
+AND next_repeat_at <= ?;
+
+-- name: GetNextRepeatAt :one
+SELECT next_repeat_at FROM deliveries
+WHERE acked_at IS NULL
+AND snoozed_until IS NULL
+AND next_repeat_at IS NOT NULL
+ORDER BY next_repeat_at ASC
+LIMIT 1;
+
+-- name: GetNextRetryAt :one
+SELECT retry_at FROM deliveries
+WHERE outcome = 'failed'
+AND acked_at IS NULL
+AND retry_at IS NOT NULL
+ORDER BY retry_at ASC
+LIMIT 1;
+
+-- name: GetDeliverySinks :many
+SELECT sink FROM delivery_sinks WHERE delivery_id = ?;
+
+-- name: AddDeliverySink :exec
+INSERT INTO delivery_sinks (delivery_id, sink, sent_at) VALUES (?, ?, ?)
+ON CONFLICT (delivery_id, sink) DO NOTHING;
+
+-- name: DeliveryExists :one
+SELECT EXISTS(
+    SELECT 1 FROM deliveries
+    WHERE notification_id = ?
+    AND occurrence_at = ?
+) AS "exists";
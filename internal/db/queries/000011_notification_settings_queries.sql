@@ -0,0 +1,41 @@
+-- name: GetNotificationSettingsByNotificationId :one
+SELECT * FROM notification_settings WHERE notification_id = ?;
+
+-- name: UpdateNotificationCatchUp :exec
+UPDATE notification_settings SET catch_up = ? WHERE notification_id = ?;
+
+-- name: UpdateNotificationMaxLateness :exec
+UPDATE notification_settings SET max_lateness = ? WHERE notification_id = ?;
+
+-- name: UpdateNotificationStartsAt :exec
+UPDATE notification_settings SET starts_at = ? WHERE notification_id = ?;
+
+-- name: UpdateNotificationEndsAt :exec
+UPDATE notification_settings SET ends_at = ? WHERE notification_id = ?;
+
+-- name: UpdateNotificationMaxOccurrences :exec
+UPDATE notification_settings SET max_occurrences = ? WHERE notification_id = ?;
+
+-- name: UpdateNotificationCalendar :exec
+UPDATE notification_settings SET calendar = ? WHERE notification_id = ?;
+
+-- name: UpdateNotificationShiftSkipped :exec
+UPDATE notification_settings SET shift_skipped = ? WHERE notification_id = ?;
+
+-- name: UpdateNotificationUrgent :exec
+UPDATE notification_settings SET urgent = ? WHERE notification_id = ?;
+
+-- name: UpdateNotificationRepeatEvery :exec
+UPDATE notification_settings SET repeat_every = ? WHERE notification_id = ?;
+
+-- name: UpdateNotificationMaxRepeats :exec
+UPDATE notification_settings SET max_repeats = ? WHERE notification_id = ?;
+
+-- name: UpdateNotificationEscalateTo :exec
+UPDATE notification_settings SET escalate_to = ? WHERE notification_id = ?;
+
+-- name: UpdateNotificationChannel :exec
+UPDATE notification_settings SET channel = ? WHERE notification_id = ?;
+
+-- name: UpdateNotificationTemplate :exec
+UPDATE notification_settings SET template = ? WHERE notification_id = ?;
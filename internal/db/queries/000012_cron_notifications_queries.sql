@@ -0,0 +1,11 @@
+-- name: GetCronNotificationByNotificationId :one
+SELECT * FROM cron_notifications WHERE notification_id = ?;
+
+-- name: CreateCronNotification :exec
+INSERT INTO cron_notifications (notification_id, expression) VALUES (?, ?);
+
+-- name: UpdateCronNotification :exec
+UPDATE cron_notifications SET expression = ? WHERE notification_id = ?;
+
+-- name: GetCronNotifications :many
+SELECT * FROM cron_notifications;
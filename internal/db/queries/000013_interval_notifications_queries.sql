@@ -0,0 +1,8 @@
+-- name: GetIntervalNotificationByNotificationId :one
+SELECT * FROM interval_notifications WHERE notification_id = ?;
+
+-- name: CreateIntervalNotification :exec
+INSERT INTO interval_notifications (notification_id, every, unit, anchor, last_day) VALUES (?, ?, ?, ?, ?);
+
+-- name: UpdateIntervalNotification :exec
+UPDATE interval_notifications SET every = ?, unit = ?, anchor = ?, last_day = ? WHERE notification_id = ?;
@@ -0,0 +1,24 @@
+-- name: GetRecurringNotificationTimesByNotificationId :many
+SELECT * FROM recurring_notification_times WHERE recurring_notification_id = ? ORDER BY trigger_at_time ASC;
+
+-- name: CreateRecurringNotificationTime :exec
+INSERT INTO recurring_notification_times (recurring_notification_id, trigger_at_time) VALUES (?, ?);
+
+-- name: RecurringNotificationHasTime :one
+SELECT EXISTS(
+    SELECT 1 FROM recurring_notification_times
+    WHERE recurring_notification_id = ?
+    AND trigger_at_time = ?
+) AS "exists";
+
+-- name: CountRecurringNotificationTimes :one
+SELECT COUNT(*) FROM recurring_notification_times WHERE recurring_notification_id = ?;
+
+-- name: DeleteRecurringNotificationTime :exec
+DELETE FROM recurring_notification_times WHERE recurring_notification_id = ? AND trigger_at_time = ?;
+
+-- name: DeleteRecurringNotificationTimesByNotificationId :exec
+DELETE FROM recurring_notification_times WHERE recurring_notification_id = ?;
+
+-- name: GetRecurringNotificationTimes :many
+SELECT * FROM recurring_notification_times;
@@ -0,0 +1,38 @@
+-- name: GetNotificationSkipDates :many
+SELECT date FROM notification_skip_dates WHERE notification_id = ? ORDER BY date ASC;
+
+-- name: CreateNotificationSkipDate :exec
+INSERT INTO notification_skip_dates (notification_id, date) VALUES (?, ?)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteNotificationSkipDate :execrows
+DELETE FROM notification_skip_dates WHERE notification_id = ? AND date = ?;
+
+-- name: GetHolidayCalendars :many
+SELECT holiday_calendars.name, COUNT(holiday_calendar_dates.date) AS dates
+FROM holiday_calendars
+LEFT JOIN holiday_calendar_dates ON holiday_calendar_dates.calendar = holiday_calendars.name
+GROUP BY holiday_calendars.name
+ORDER BY holiday_calendars.name ASC;
+
+-- name: HolidayCalendarExists :one
+SELECT EXISTS(
+    SELECT 1 FROM holiday_calendars WHERE name = ?
+) AS "exists";
+
+-- name: CreateHolidayCalendar :exec
+INSERT INTO holiday_calendars (name) VALUES (?)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteHolidayCalendar :execrows
+DELETE FROM holiday_calendars WHERE name = ?;
+
+-- name: GetHolidayCalendarDates :many
+SELECT * FROM holiday_calendar_dates WHERE calendar = ? ORDER BY date ASC;
+
+-- name: CreateHolidayCalendarDate :exec
+INSERT INTO holiday_calendar_dates (calendar, date, description) VALUES (?, ?, ?)
+ON CONFLICT (calendar, date) DO UPDATE SET description = excluded.description;
+
+-- name: DeleteHolidayCalendarDates :exec
+DELETE FROM holiday_calendar_dates WHERE calendar = ?;
@@ -7,34 +7,16 @@ package sqlc
 
 import (
 	"context"
-	"time"
 )
 
-const createMessageFeature = `-- name: CreateMessageFeature :exec
-INSERT INTO messages_features (message_id, feature_name) VALUES (?, ?)
+const createFeatureIfNotExists = `-- name: CreateFeatureIfNotExists :exec
+INSERT INTO features (name, seq)
+SELECT ?, COALESCE(MAX(seq), 0) + 1 FROM features WHERE true
+ON CONFLICT (name) DO NOTHING
 `
 
-type CreateMessageFeatureParams struct {
-	MessageID   int64
-	FeatureName string
-}
-
-func (q *Queries) CreateMessageFeature(ctx context.Context, arg CreateMessageFeatureParams) error {
-	_, err := q.db.ExecContext(ctx, createMessageFeature, arg.MessageID, arg.FeatureName)
-	return err
-}
-
-const decrementMessageFeatureCount = `-- name: DecrementMessageFeatureCount :exec
-UPDATE messages_features SET count = count - 1 WHERE message_id = ? AND feature_name = ?
-`
-
-type DecrementMessageFeatureCountParams struct {
-	MessageID   int64
-	FeatureName string
-}
-
-func (q *Queries) DecrementMessageFeatureCount(ctx context.Context, arg DecrementMessageFeatureCountParams) error {
-	_, err := q.db.ExecContext(ctx, decrementMessageFeatureCount, arg.MessageID, arg.FeatureName)
+func (q *Queries) CreateFeatureIfNotExists(ctx context.Context, name string) error {
+	_, err := q.db.ExecContext(ctx, createFeatureIfNotExists, name)
 	return err
 }
 
@@ -111,64 +93,6 @@ func (q *Queries) GetFeaturesByMessageId(ctx context.Context, messageID int64) (
 	return items, nil
 }
 
-const getMessageAndFeatures = `-- name: GetMessageAndFeatures :one
-SELECT 
-  messages.id, messages.text, messages.created_at, messages.updated_at,
-  GROUP_CONCAT(messages_features.feature_name, ', ') AS features
-FROM messages
-INNER JOIN messages_features ON messages_features.message_id = messages.id
-WHERE messages.id = ? AND messages_features.count > 0
-GROUP BY messages.id
-`
-
-type GetMessageAndFeaturesRow struct {
-	ID        int64
-	Text      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Features  string
-}
-
-func (q *Queries) GetMessageAndFeatures(ctx context.Context, id int64) (GetMessageAndFeaturesRow, error) {
-	row := q.db.QueryRowContext(ctx, getMessageAndFeatures, id)
-	var i GetMessageAndFeaturesRow
-	err := row.Scan(
-		&i.ID,
-		&i.Text,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Features,
-	)
-	return i, err
-}
-
-const getPrettyFeaturesByMessageId = `-- name: GetPrettyFeaturesByMessageId :one
-SELECT GROUP_CONCAT(SUBSTR(feature_name, 1, 3), ', ')
-FROM messages_features 
-WHERE message_id = ? AND count > 0
-`
-
-func (q *Queries) GetPrettyFeaturesByMessageId(ctx context.Context, messageID int64) (string, error) {
-	row := q.db.QueryRowContext(ctx, getPrettyFeaturesByMessageId, messageID)
-	var group_concat string
-	err := row.Scan(&group_concat)
-	return group_concat, err
-}
-
-const incrementMessageFeatureCount = `-- name: IncrementMessageFeatureCount :exec
-UPDATE messages_features SET count = count + 1 WHERE message_id = ? AND feature_name = ?
-`
-
-type IncrementMessageFeatureCountParams struct {
-	MessageID   int64
-	FeatureName string
-}
-
-func (q *Queries) IncrementMessageFeatureCount(ctx context.Context, arg IncrementMessageFeatureCountParams) error {
-	_, err := q.db.ExecContext(ctx, incrementMessageFeatureCount, arg.MessageID, arg.FeatureName)
-	return err
-}
-
 const messageHasFeature = `-- name: MessageHasFeature :one
 SELECT EXISTS(
     SELECT 1 FROM messages_features 
@@ -7,10 +7,43 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 )
 
+const countNotificationsByMessage = `-- name: CountNotificationsByMessage :many
+SELECT message_id, COUNT(*) AS count FROM notifications GROUP BY message_id
+`
+
+type CountNotificationsByMessageRow struct {
+	MessageID int64
+	Count     int64
+}
+
+func (q *Queries) CountNotificationsByMessage(ctx context.Context) ([]CountNotificationsByMessageRow, error) {
+	rows, err := q.db.QueryContext(ctx, countNotificationsByMessage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountNotificationsByMessageRow
+	for rows.Next() {
+		var i CountNotificationsByMessageRow
+		if err := rows.Scan(&i.MessageID, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createNotification = `-- name: CreateNotification :one
-INSERT INTO notifications (message_id, type) VALUES (?, ?) RETURNING id, message_id, type, created_at, updated_at
+INSERT INTO notifications (message_id, type) VALUES (?, ?) RETURNING id, message_id, type, created_at, updated_at, finished_at
 `
 
 type CreateNotificationParams struct {
@@ -27,6 +60,7 @@ func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotification
 		&i.Type,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FinishedAt,
 	)
 	return i, err
 }
@@ -51,9 +85,23 @@ func (q *Queries) DeleteNotificationByIdReturningMsgId(ctx context.Context, id i
 	return message_id, err
 }
 
+const finishNotification = `-- name: FinishNotification :exec
+UPDATE notifications SET finished_at = ? WHERE id = ?
+`
+
+type FinishNotificationParams struct {
+	FinishedAt sql.NullTime
+	ID         int64
+}
+
+func (q *Queries) FinishNotification(ctx context.Context, arg FinishNotificationParams) error {
+	_, err := q.db.ExecContext(ctx, finishNotification, arg.FinishedAt, arg.ID)
+	return err
+}
+
 const getNotificationAndMessageById = `-- name: GetNotificationAndMessageById :one
 SELECT 
-    notifications.id, notifications.message_id, notifications.type, notifications.created_at, notifications.updated_at,
+    notifications.id, notifications.message_id, notifications.type, notifications.created_at, notifications.updated_at, notifications.finished_at,
     messages.id, messages.text, messages.created_at, messages.updated_at
 FROM notifications
 INNER JOIN messages ON messages.id = notifications.message_id
@@ -75,6 +123,7 @@ func (q *Queries) GetNotificationAndMessageById(ctx context.Context, id int64) (
 		&i.Notification.Type,
 		&i.Notification.CreatedAt,
 		&i.Notification.UpdatedAt,
+		&i.Notification.FinishedAt,
 		&i.Message.ID,
 		&i.Message.Text,
 		&i.Message.CreatedAt,
@@ -84,7 +133,7 @@ func (q *Queries) GetNotificationAndMessageById(ctx context.Context, id int64) (
 }
 
 const getNotificationById = `-- name: GetNotificationById :one
-SELECT id, message_id, type, created_at, updated_at FROM notifications WHERE id = ?
+SELECT id, message_id, type, created_at, updated_at, finished_at FROM notifications WHERE id = ?
 `
 
 func (q *Queries) GetNotificationById(ctx context.Context, id int64) (Notification, error) {
@@ -96,12 +145,13 @@ func (q *Queries) GetNotificationById(ctx context.Context, id int64) (Notificati
 		&i.Type,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FinishedAt,
 	)
 	return i, err
 }
 
 const getNotifications = `-- name: GetNotifications :many
-SELECT id, message_id, type, created_at, updated_at FROM notifications
+SELECT id, message_id, type, created_at, updated_at, finished_at FROM notifications
 `
 
 func (q *Queries) GetNotifications(ctx context.Context) ([]Notification, error) {
@@ -119,6 +169,41 @@ func (q *Queries) GetNotifications(ctx context.Context) ([]Notification, error)
 			&i.Type,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotificationsByMessageId = `-- name: GetNotificationsByMessageId :many
+SELECT id, message_id, type, created_at, updated_at, finished_at FROM notifications WHERE message_id = ?
+`
+
+func (q *Queries) GetNotificationsByMessageId(ctx context.Context, messageID int64) ([]Notification, error) {
+	rows, err := q.db.QueryContext(ctx, getNotificationsByMessageId, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.MessageID,
+			&i.Type,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -134,7 +219,7 @@ func (q *Queries) GetNotifications(ctx context.Context) ([]Notification, error)
 }
 
 const getNotificationsOrderByCreatedAtASC = `-- name: GetNotificationsOrderByCreatedAtASC :many
-SELECT id, message_id, type, created_at, updated_at FROM notifications ORDER BY created_at ASC
+SELECT id, message_id, type, created_at, updated_at, finished_at FROM notifications ORDER BY created_at ASC
 `
 
 func (q *Queries) GetNotificationsOrderByCreatedAtASC(ctx context.Context) ([]Notification, error) {
@@ -152,6 +237,7 @@ func (q *Queries) GetNotificationsOrderByCreatedAtASC(ctx context.Context) ([]No
 			&i.Type,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -167,7 +253,7 @@ func (q *Queries) GetNotificationsOrderByCreatedAtASC(ctx context.Context) ([]No
 }
 
 const getNotificationsOrderByCreatedAtDESC = `-- name: GetNotificationsOrderByCreatedAtDESC :many
-SELECT id, message_id, type, created_at, updated_at FROM notifications ORDER BY created_at DESC
+SELECT id, message_id, type, created_at, updated_at, finished_at FROM notifications ORDER BY created_at DESC
 `
 
 func (q *Queries) GetNotificationsOrderByCreatedAtDESC(ctx context.Context) ([]Notification, error) {
@@ -185,6 +271,7 @@ func (q *Queries) GetNotificationsOrderByCreatedAtDESC(ctx context.Context) ([]N
 			&i.Type,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -200,7 +287,7 @@ func (q *Queries) GetNotificationsOrderByCreatedAtDESC(ctx context.Context) ([]N
 }
 
 const getNotificationsOrderByTypeASC = `-- name: GetNotificationsOrderByTypeASC :many
-SELECT id, message_id, type, created_at, updated_at FROM notifications ORDER BY type ASC
+SELECT id, message_id, type, created_at, updated_at, finished_at FROM notifications ORDER BY type ASC
 `
 
 func (q *Queries) GetNotificationsOrderByTypeASC(ctx context.Context) ([]Notification, error) {
@@ -218,6 +305,7 @@ func (q *Queries) GetNotificationsOrderByTypeASC(ctx context.Context) ([]Notific
 			&i.Type,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -233,7 +321,7 @@ func (q *Queries) GetNotificationsOrderByTypeASC(ctx context.Context) ([]Notific
 }
 
 const getNotificationsOrderByTypeDESC = `-- name: GetNotificationsOrderByTypeDESC :many
-SELECT id, message_id, type, created_at, updated_at FROM notifications ORDER BY type DESC
+SELECT id, message_id, type, created_at, updated_at, finished_at FROM notifications ORDER BY type DESC
 `
 
 func (q *Queries) GetNotificationsOrderByTypeDESC(ctx context.Context) ([]Notification, error) {
@@ -251,6 +339,7 @@ func (q *Queries) GetNotificationsOrderByTypeDESC(ctx context.Context) ([]Notifi
 			&i.Type,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -266,7 +355,7 @@ func (q *Queries) GetNotificationsOrderByTypeDESC(ctx context.Context) ([]Notifi
 }
 
 const getNotificationsOrderByUpdatedAtASC = `-- name: GetNotificationsOrderByUpdatedAtASC :many
-SELECT id, message_id, type, created_at, updated_at FROM notifications ORDER BY updated_at ASC
+SELECT id, message_id, type, created_at, updated_at, finished_at FROM notifications ORDER BY updated_at ASC
 `
 
 func (q *Queries) GetNotificationsOrderByUpdatedAtASC(ctx context.Context) ([]Notification, error) {
@@ -284,6 +373,7 @@ func (q *Queries) GetNotificationsOrderByUpdatedAtASC(ctx context.Context) ([]No
 			&i.Type,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -299,7 +389,7 @@ func (q *Queries) GetNotificationsOrderByUpdatedAtASC(ctx context.Context) ([]No
 }
 
 const getNotificationsOrderByUpdatedAtDESC = `-- name: GetNotificationsOrderByUpdatedAtDESC :many
-SELECT id, message_id, type, created_at, updated_at FROM notifications ORDER BY updated_at DESC
+SELECT id, message_id, type, created_at, updated_at, finished_at FROM notifications ORDER BY updated_at DESC
 `
 
 func (q *Queries) GetNotificationsOrderByUpdatedAtDESC(ctx context.Context) ([]Notification, error) {
@@ -317,6 +407,41 @@ func (q *Queries) GetNotificationsOrderByUpdatedAtDESC(ctx context.Context) ([]N
 			&i.Type,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnfinishedNotifications = `-- name: GetUnfinishedNotifications :many
+SELECT id, message_id, type, created_at, updated_at, finished_at FROM notifications WHERE finished_at IS NULL
+`
+
+func (q *Queries) GetUnfinishedNotifications(ctx context.Context) ([]Notification, error) {
+	rows, err := q.db.QueryContext(ctx, getUnfinishedNotifications)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.MessageID,
+			&i.Type,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FinishedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -345,3 +470,12 @@ func (q *Queries) NotificationExists(ctx context.Context, id int64) (int64, erro
 	err := row.Scan(&exists)
 	return exists, err
 }
+
+const unfinishNotification = `-- name: UnfinishNotification :exec
+UPDATE notifications SET finished_at = NULL WHERE id = ?
+`
+
+func (q *Queries) UnfinishNotification(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, unfinishNotification, id)
+	return err
+}
@@ -7,23 +7,15 @@ package sqlc
 
 import (
 	"context"
-	"database/sql"
 )
 
-const createRecurringNotification = `-- name: CreateRecurringNotification :one
-INSERT INTO recurring_notifications (notification_id, trigger_at_time) VALUES (?, ?) RETURNING notification_id, trigger_at_time
+const createRecurringNotification = `-- name: CreateRecurringNotification :exec
+INSERT INTO recurring_notifications (notification_id) VALUES (?)
 `
 
-type CreateRecurringNotificationParams struct {
-	NotificationID int64
-	TriggerAtTime  sql.NullString
-}
-
-func (q *Queries) CreateRecurringNotification(ctx context.Context, arg CreateRecurringNotificationParams) (RecurringNotification, error) {
-	row := q.db.QueryRowContext(ctx, createRecurringNotification, arg.NotificationID, arg.TriggerAtTime)
-	var i RecurringNotification
-	err := row.Scan(&i.NotificationID, &i.TriggerAtTime)
-	return i, err
+func (q *Queries) CreateRecurringNotification(ctx context.Context, notificationID int64) error {
+	_, err := q.db.ExecContext(ctx, createRecurringNotification, notificationID)
+	return err
 }
 
 const createRecurringNotificationDay = `-- name: CreateRecurringNotificationDay :exec
@@ -55,14 +47,14 @@ func (q *Queries) DeleteRecurringNotificationDayByNotificationId(ctx context.Con
 }
 
 const getRecurringNotificationByNotificationId = `-- name: GetRecurringNotificationByNotificationId :one
-SELECT notification_id, trigger_at_time FROM recurring_notifications WHERE notification_id = ?
+SELECT notification_id FROM recurring_notifications WHERE notification_id = ?
 `
 
-func (q *Queries) GetRecurringNotificationByNotificationId(ctx context.Context, notificationID int64) (RecurringNotification, error) {
+func (q *Queries) GetRecurringNotificationByNotificationId(ctx context.Context, notificationID int64) (int64, error) {
 	row := q.db.QueryRowContext(ctx, getRecurringNotificationByNotificationId, notificationID)
-	var i RecurringNotification
-	err := row.Scan(&i.NotificationID, &i.TriggerAtTime)
-	return i, err
+	var notification_id int64
+	err := row.Scan(&notification_id)
+	return notification_id, err
 }
 
 const getRecurringNotificationDaysByNotificationId = `-- name: GetRecurringNotificationDaysByNotificationId :many
@@ -111,19 +103,3 @@ func (q *Queries) RecurringNotificationHasDay(ctx context.Context, arg Recurring
 	err := row.Scan(&exists)
 	return exists, err
 }
-
-const updateRecurringNotification = `-- name: UpdateRecurringNotification :one
-UPDATE recurring_notifications SET trigger_at_time = ? WHERE notification_id = ? RETURNING notification_id, trigger_at_time
-`
-
-type UpdateRecurringNotificationParams struct {
-	TriggerAtTime  sql.NullString
-	NotificationID int64
-}
-
-func (q *Queries) UpdateRecurringNotification(ctx context.Context, arg UpdateRecurringNotificationParams) (RecurringNotification, error) {
-	row := q.db.QueryRowContext(ctx, updateRecurringNotification, arg.TriggerAtTime, arg.NotificationID)
-	var i RecurringNotification
-	err := row.Scan(&i.NotificationID, &i.TriggerAtTime)
-	return i, err
-}
@@ -9,6 +9,38 @@ import (
 	"context"
 )
 
+const countTodosByMessage = `-- name: CountTodosByMessage :many
+SELECT message_id, COUNT(*) AS count FROM todos GROUP BY message_id
+`
+
+type CountTodosByMessageRow struct {
+	MessageID int64
+	Count     int64
+}
+
+func (q *Queries) CountTodosByMessage(ctx context.Context) ([]CountTodosByMessageRow, error) {
+	rows, err := q.db.QueryContext(ctx, countTodosByMessage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountTodosByMessageRow
+	for rows.Next() {
+		var i CountTodosByMessageRow
+		if err := rows.Scan(&i.MessageID, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createTodo = `-- name: CreateTodo :one
 INSERT INTO todos (message_id) VALUES (?) RETURNING id, message_id, status, created_at, updated_at
 `
@@ -0,0 +1,7 @@
+package sqlc
+
+// DB returns what the queries run on, the database or a transaction, so
+// features keeping queries of their own can run them alongside.
+func (q *Queries) DB() DBTX {
+	return q.db
+}
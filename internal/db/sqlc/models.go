@@ -9,11 +9,66 @@ import (
 	"time"
 )
 
+type CatchUpEnum struct {
+	Name string
+	Seq  sql.NullInt64
+}
+
+type CronNotification struct {
+	NotificationID int64
+	Expression     string
+}
+
+type Delivery struct {
+	ID             int64
+	NotificationID int64
+	OccurrenceAt   time.Time
+	Outcome        string
+	Attempts       int64
+	Error          sql.NullString
+	ClaimedAt      time.Time
+	DeliveredAt    sql.NullTime
+	SnoozedUntil   sql.NullTime
+	AckedAt        sql.NullTime
+	Repeats        int64
+	NextRepeatAt   sql.NullTime
+	RetryAt        sql.NullTime
+}
+
+type DeliverySink struct {
+	DeliveryID int64
+	Sink       string
+	SentAt     time.Time
+}
+
 type Feature struct {
 	Name string
 	Seq  int64
 }
 
+type HolidayCalendar struct {
+	Name string
+}
+
+type HolidayCalendarDate struct {
+	Calendar    string
+	Date        string
+	Description string
+}
+
+type IntervalNotification struct {
+	NotificationID int64
+	Every          int64
+	Unit           string
+	Anchor         time.Time
+	LastDay        bool
+}
+
+type IntervalUnitEnum struct {
+	Name string
+	Seq  sql.NullInt64
+}
+
 type Message struct {
 	ID        int64
 	Text      string
@@ -27,17 +82,53 @@ type MessagesFeature struct {
 	Count       int64
 }
 
+type MultiNotification struct {
+	NotificationID int64
+}
+
+type MultiNotificationDate struct {
+	MultiNotificationID int64
+	TriggerAt           time.Time
+}
+
 type Notification struct {
-	ID        int64
-	MessageID int64
-	Type      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID         int64
+	MessageID  int64
+	Type       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FinishedAt sql.NullTime
+}
+
+type NotificationSetting struct {
+	NotificationID int64
+	CatchUp        string
+	MaxLateness    sql.NullInt64
+	StartsAt       sql.NullTime
+	EndsAt         sql.NullTime
+	MaxOccurrences sql.NullInt64
+	Calendar       sql.NullString
+	ShiftSkipped   bool
+	Urgent         bool
+	RepeatEvery    sql.NullInt64
+	MaxRepeats     sql.NullInt64
+	EscalateTo     sql.NullString
+	Channel        sql.NullString
+	Template       sql.NullString
+}
+
+type NotificationSkipDate struct {
+	NotificationID int64
+	Date           string
+}
+
+type OutcomeEnum struct {
+	Name string
+	Seq  sql.NullInt64
 }
 
 type RecurringNotification struct {
 	NotificationID int64
-	TriggerAtTime  sql.NullString
 }
 
 type RecurringNotificationDay struct {
@@ -45,6 +136,11 @@ type RecurringNotificationDay struct {
 	WeekDay                 string
 }
 
+type RecurringNotificationTime struct {
+	RecurringNotificationID int64
+	TriggerAtTime           string
+}
+
 type SimpleNotification struct {
 	NotificationID int64
 	TriggerAt      time.Time
@@ -0,0 +1,390 @@
+package doctor
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/feat"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+type check struct {
+	name     string
+	fixable  bool
+	problems []string
+}
+
+func integrityCheck(ctx context.Context, db *sql.DB) (check, error) {
+	c := check{name: "integrity check"}
+
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil { return c, err }
+	defer rows.Close()
+
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil { return c, err }
+		if result != "ok" { c.problems = append(c.problems, result) }
+	}
+	return c, rows.Err()
+}
+
+func foreignKeyCheck(ctx context.Context, db *sql.DB) (check, error) {
+	c := check{name: "foreign keys"}
+
+	rows, err := db.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil { return c, err }
+	defer rows.Close()
+
+	for rows.Next() {
+		var table, parent string
+		var rowId sql.NullInt64
+		var fkId int64
+		if err := rows.Scan(&table, &rowId, &parent, &fkId); err != nil { return c, err }
+		c.problems = append(c.problems, fmt.Sprintf("%s row (%d) references a missing %s row", table, rowId.Int64, parent))
+	}
+	return c, rows.Err()
+}
+
+// featureCount is a messages_features count that doesn't match the rows the
+// feature has for the message.
+type featureCount struct {
+	messageID   int64
+	featureName string
+	stored      int64
+	actual      int64
+}
+
+// mismatchedCounts compares the messages_features counts with the ones every
+// registered feature reports.
+func mismatchedCounts(ctx context.Context, queries *sqlc.Queries) ([]featureCount, error) {
+	stored, err := queries.GetPositiveMessageFeatures(ctx)
+	if err != nil { return nil, err }
+
+	mismatched := []featureCount{}
+	for _, f := range feat.All() {
+		actual, err := f.Counts(ctx, queries)
+		if err != nil { return nil, err }
+
+		seen := map[int64]bool{}
+		for _, s := range stored {
+			if s.FeatureName != f.Name() { continue }
+			seen[s.MessageID] = true
+			if s.Count != actual[s.MessageID] {
+				mismatched = append(mismatched, featureCount{s.MessageID, f.Name(), s.Count, actual[s.MessageID]})
+			}
+		}
+		for msgId, count := range actual {
+			if !seen[msgId] && count > 0 {
+				mismatched = append(mismatched, featureCount{msgId, f.Name(), 0, count})
+			}
+		}
+	}
+
+	slices.SortFunc(mismatched, func(a featureCount, b featureCount) int {
+		if c := cmp.Compare(a.messageID, b.messageID); c != 0 { return c }
+		return strings.Compare(a.featureName, b.featureName)
+	})
+	return mismatched, nil
+}
+
+func runChecks(ctx context.Context, db *sql.DB, queries *sqlc.Queries) ([]check, error) {
+	checks := []check{}
+
+	integrity, err := integrityCheck(ctx, db)
+	if err != nil { return nil, err }
+	checks = append(checks, integrity)
+
+	foreignKeys, err := foreignKeyCheck(ctx, db)
+	if err != nil { return nil, err }
+	checks = append(checks, foreignKeys)
+
+	nonPositive := check{name: "message features with zero or negative count", fixable: true}
+	features, err := queries.GetNonPositiveMessageFeatures(ctx)
+	if err != nil { return nil, err }
+	for _, f := range features {
+		nonPositive.problems = append(nonPositive.problems,
+			fmt.Sprintf("message (%d) %s: count %d", f.MessageID, f.FeatureName, f.Count))
+	}
+	checks = append(checks, nonPositive)
+
+	mismatched := check{name: "message feature counts", fixable: true}
+	counts, err := mismatchedCounts(ctx, queries)
+	if err != nil { return nil, err }
+	for _, c := range counts {
+		mismatched.problems = append(mismatched.problems,
+			fmt.Sprintf("message (%d) %s: stored %d, actual %d", c.messageID, c.featureName, c.stored, c.actual))
+	}
+	checks = append(checks, mismatched)
+
+	orphanDetails := check{name: "orphaned notification details", fixable: true}
+	simple, err := queries.GetOrphanSimpleNotifications(ctx)
+	if err != nil { return nil, err }
+	for _, n := range simple {
+		orphanDetails.problems = append(orphanDetails.problems,
+			fmt.Sprintf("simple notification details for missing notification (%d)", n.NotificationID))
+	}
+	recurring, err := queries.GetOrphanRecurringNotifications(ctx)
+	if err != nil { return nil, err }
+	for _, id := range recurring {
+		orphanDetails.problems = append(orphanDetails.problems,
+			fmt.Sprintf("recurring notification details for missing notification (%d)", id))
+	}
+	multi, err := queries.GetOrphanMultiNotifications(ctx)
+	if err != nil { return nil, err }
+	for _, id := range multi {
+		orphanDetails.problems = append(orphanDetails.problems,
+			fmt.Sprintf("multi notification details for missing notification (%d)", id))
+	}
+	cron, err := queries.GetOrphanCronNotifications(ctx)
+	if err != nil { return nil, err }
+	for _, n := range cron {
+		orphanDetails.problems = append(orphanDetails.problems,
+			fmt.Sprintf("cron notification details for missing notification (%d)", n.NotificationID))
+	}
+	interval, err := queries.GetOrphanIntervalNotifications(ctx)
+	if err != nil { return nil, err }
+	for _, id := range interval {
+		orphanDetails.problems = append(orphanDetails.problems,
+			fmt.Sprintf("interval notification details for missing notification (%d)", id))
+	}
+	checks = append(checks, orphanDetails)
+
+	withoutDetails := check{name: "notifications without details"}
+	notifications, err := queries.GetNotificationsWithoutDetails(ctx)
+	if err != nil { return nil, err }
+	for _, n := range notifications {
+		if n.Type == "multi" {
+			withoutDetails.problems = append(withoutDetails.problems,
+				fmt.Sprintf("multi notification (%d) has no dates", n.ID))
+			continue
+		}
+		if n.Type == "recurring" {
+			withoutDetails.problems = append(withoutDetails.problems,
+				fmt.Sprintf("recurring notification (%d) has no trigger times", n.ID))
+			continue
+		}
+		withoutDetails.problems = append(withoutDetails.problems,
+			fmt.Sprintf("%s notification (%d) has no %s_notifications row", n.Type, n.ID, n.Type))
+	}
+	checks = append(checks, withoutDetails)
+
+	malformed := check{name: "malformed trigger times"}
+	recurringTimes, err := queries.GetRecurringNotificationTimes(ctx)
+	if err != nil { return nil, err }
+	for _, t := range recurringTimes {
+		if _, err := time.Parse("15-04-05", t.TriggerAtTime); err != nil {
+			malformed.problems = append(malformed.problems,
+				fmt.Sprintf("recurring notification (%d) trigger_at_time \"%s\" is not HH-MM-SS", t.RecurringNotificationID, t.TriggerAtTime))
+		}
+	}
+	cronNotifications, err := queries.GetCronNotifications(ctx)
+	if err != nil { return nil, err }
+	for _, n := range cronNotifications {
+		if _, err := utils.ParseCron(n.Expression); err != nil {
+			malformed.problems = append(malformed.problems,
+				fmt.Sprintf("cron notification (%d): %s", n.NotificationID, err))
+		}
+	}
+	checks = append(checks, malformed)
+
+	return checks, nil
+}
+
+func fixProblems(ctx context.Context, db *sql.DB, queries *sqlc.Queries) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil { return err }
+	qtx := queries.WithTx(tx)
+
+	// Orphaned details go first, then the counts. Notifications without
+	// details are left alone: deleting one would take its deliveries and
+	// settings along, so that's for the user to decide.
+	simple, err := qtx.GetOrphanSimpleNotifications(ctx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, n := range simple {
+		if err := qtx.DeleteSimpleNotification(ctx, n.NotificationID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	recurring, err := qtx.GetOrphanRecurringNotifications(ctx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, id := range recurring {
+		if err := qtx.DeleteRecurringNotification(ctx, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	multi, err := qtx.GetOrphanMultiNotifications(ctx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, id := range multi {
+		if err := qtx.DeleteMultiNotification(ctx, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	cron, err := qtx.GetOrphanCronNotifications(ctx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, n := range cron {
+		if err := qtx.DeleteCronNotification(ctx, n.NotificationID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	interval, err := qtx.GetOrphanIntervalNotifications(ctx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, id := range interval {
+		if err := qtx.DeleteIntervalNotification(ctx, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	features, err := qtx.GetNonPositiveMessageFeatures(ctx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, f := range features {
+		if err := qtx.DeleteMessageFeature(ctx, sqlc.DeleteMessageFeatureParams{
+			MessageID: f.MessageID,
+			FeatureName: f.FeatureName,
+		}); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	counts, err := mismatchedCounts(ctx, qtx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, c := range counts {
+		if c.actual == 0 {
+			err = qtx.DeleteMessageFeature(ctx, sqlc.DeleteMessageFeatureParams{
+				MessageID: c.messageID,
+				FeatureName: c.featureName,
+			})
+		} else {
+			err = qtx.SetMessageFeatureCount(ctx, sqlc.SetMessageFeatureCountParams{
+				MessageID: c.messageID,
+				FeatureName: c.featureName,
+				Count: c.actual,
+			})
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func printReport(checks []check) (int, int) {
+	problemsCount, fixableCount := 0, 0
+	for _, c := range checks {
+		if len(c.problems) == 0 {
+			fmt.Printf("[ok] %s\n", c.name)
+			continue
+		}
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("[!!] %s: %d problem", c.name, len(c.problems)))
+		if len(c.problems) > 1 { sb.WriteString("s") }
+		if !c.fixable { sb.WriteString(" (can't be fixed automatically)") }
+		fmt.Println(sb.String())
+
+		for _, p := range c.problems {
+			fmt.Printf("\t%s\n", p)
+		}
+
+		problemsCount += len(c.problems)
+		if c.fixable { fixableCount += len(c.problems) }
+	}
+	return problemsCount, fixableCount
+}
+
+func doctor(fix bool) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+	defer db.Close()
+
+	queries := sqlc.New(db)
+
+	checks, err := runChecks(ctx, db, queries)
+	if err != nil { return err }
+
+	problemsCount, fixableCount := printReport(checks)
+	fmt.Println()
+
+	if problemsCount == 0 {
+		fmt.Println("No problems found.")
+		return nil
+	}
+	if !fix {
+		fmt.Printf("%d problem(s) found, %d can be fixed with '-fix'.\n", problemsCount, fixableCount)
+		return nil
+	}
+	if fixableCount == 0 {
+		fmt.Printf("%d problem(s) found, none can be fixed automatically.\n", problemsCount)
+		return nil
+	}
+
+	if err := fixProblems(ctx, db, queries); err != nil { return err }
+
+	checks, err = runChecks(ctx, db, queries)
+	if err != nil { return err }
+
+	remaining := 0
+	for _, c := range checks {
+		remaining += len(c.problems)
+	}
+	fmt.Printf("Fixed %d problem(s), %d remaining.\n", problemsCount-remaining, remaining)
+
+	return nil
+}
+
+func Cmd(args []string) {
+	cmd := flag.NewFlagSet("doctor", flag.ExitOnError)
+	fixFlag := cmd.Bool("fix", false, "repair the problems that can be fixed, in a single transaction")
+
+	if err := cmd.Parse(args); err != nil {
+		fmt.Printf("error parsing cli args: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := doctor(*fixFlag); err != nil {
+		fmt.Printf("error checking database: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -3,73 +3,150 @@ package feat
 import (
 	"context"
 	"fmt"
+	"io/fs"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/matheusbucater/gmess/internal/db/migrations"
 	"github.com/matheusbucater/gmess/internal/db/sqlc"
 	"github.com/matheusbucater/gmess/internal/utils"
 )
 
-type FeatureEnum int
-const (
-	E_notifications_feature FeatureEnum = iota
-	E_todos_feature 
-	E_lists_feature 
-	E_feature_not_available
-)
-var featureName = map[FeatureEnum]string{
-	E_notifications_feature: "notifications",
-	E_todos_feature: "todos",
-	E_lists_feature: "todos",
-	E_feature_not_available: "not_available",
+// Feature is something built on top of messages. Features register
+// themselves from an init function and main dispatches to them by name.
+type Feature interface {
+	// Name is both the subcommand and the name stored in the features table.
+	Name() string
+	// Migrations holds the feature's own migrations, applied after the core
+	// ones. It can be nil when the feature's tables live in the core
+	// migrations.
+	Migrations() fs.FS
+	Cmd(args []string)
+	// OnMessageDelete runs inside the transaction that deletes the message,
+	// before the message row is removed.
+	OnMessageDelete(ctx context.Context, queries *sqlc.Queries, msgId int64) error
+	// Details renders the lines shown for the message by "show -id".
+	Details(ctx context.Context, queries *sqlc.Queries, msgId int64) (string, error)
+	// Badge is the short text shown next to the message by "show". count is
+	// the messages_features count of the message for this feature.
+	Badge(ctx context.Context, queries *sqlc.Queries, msgId int64, count int64) (string, error)
+	// Fields is what templates can use of the message's side of the feature,
+	// e.g. the status of its todo. It can be nil.
+	Fields(ctx context.Context, queries *sqlc.Queries, msgId int64) (map[string]any, error)
+	// Counts returns how many of the feature's rows each message has, by
+	// message id. Doctor checks the messages_features counts against it.
+	Counts(ctx context.Context, queries *sqlc.Queries) (map[int64]int64, error)
 }
-func (fe FeatureEnum) String() string {
-	return featureName[fe]
+
+var registry []Feature
+
+// Register makes a feature available. It panics if the name is taken.
+func Register(f Feature) {
+	if _, ok := Get(f.Name()); ok {
+		panic("feat: feature \"" + f.Name() + "\" registered twice")
+	}
+	registry = append(registry, f)
+
+	if m := f.Migrations(); m != nil {
+		migrations.Register(migrations.Source{Name: f.Name(), FS: m})
+	}
 }
 
-func FeatureExists(feat string) (bool, error) {
-	ctx := context.Background()
-	db, err := utils.DbConnect(ctx)
-	if err != nil {
-		return false, err
+func Get(name string) (Feature, bool) {
+	i := slices.IndexFunc(registry, func(f Feature) bool { return f.Name() == name })
+	if i == -1 { return nil, false }
+	return registry[i], true
+}
+
+func All() []Feature {
+	return slices.Clone(registry)
+}
+
+// DefaultBadge is the badge used by features that don't need anything fancier.
+func DefaultBadge(name string) string {
+	if len(name) <= 3 { return name }
+	return name[:3]
+}
+
+// MessageBadges returns the badge of every feature the message has.
+func MessageBadges(ctx context.Context, queries *sqlc.Queries, msgId int64) ([]string, error) {
+	features, err := queries.GetFeaturesByMessageId(ctx, msgId)
+	if err != nil { return nil, err }
+
+	badges := []string{}
+	for _, f := range features {
+		if f.Count <= 0 { continue }
+
+		feature, ok := Get(f.FeatureName)
+		if !ok {
+			badges = append(badges, DefaultBadge(f.FeatureName))
+			continue
+		}
+
+		badge, err := feature.Badge(ctx, queries, msgId, f.Count)
+		if err != nil { return nil, err }
+		badges = append(badges, badge)
 	}
-	queries := sqlc.New(db)
+	return badges, nil
+}
+
+// MessageFields returns the fields of every feature the message has, by
+// feature name.
+func MessageFields(ctx context.Context, queries *sqlc.Queries, msgId int64) (map[string]map[string]any, error) {
+	features, err := queries.GetFeaturesByMessageId(ctx, msgId)
+	if err != nil { return nil, err }
 
-	exists, err := queries.FeatureExists(ctx, feat)
-	return exists == 1, nil
+	fields := map[string]map[string]any{}
+	for _, f := range features {
+		if f.Count <= 0 { continue }
+
+		feature, ok := Get(f.FeatureName)
+		if !ok { continue }
+
+		featureFields, err := feature.Fields(ctx, queries, msgId)
+		if err != nil { return nil, err }
+		if featureFields != nil { fields[f.FeatureName] = featureFields }
+	}
+	return fields, nil
 }
 
-func ShowFeatures() error {
+// SyncFeatures adds every registered feature to the features table.
+func SyncFeatures() error {
 	ctx := context.Background()
 	db, err := utils.DbConnect(ctx)
 	if err != nil {
 		return err
 	}
+	defer db.Close()
 
 	queries := sqlc.New(db)
 
-	features, err := queries.GetFeatures(ctx)
-	if err != nil {
-		return err
+	for _, f := range registry {
+		if err := queries.CreateFeatureIfNotExists(ctx, f.Name()); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	featuresCount := len(features)
+func ShowFeatures() error {
+	featuresCount := len(registry)
 
 	var sb strings.Builder
 	sb.WriteString(strconv.Itoa(featuresCount))
 	sb.WriteString(" feature")
 	if featuresCount == 0 || featuresCount > 1 {
 		sb.WriteString("s")
-	} 
+	}
 	sb.WriteString(" available")
 	if featuresCount > 0 {
 		sb.WriteString("\n")
 	}
 	fmt.Println(sb.String())
-	for _, feat := range features {
-		fmt.Printf("%s\n", strings.ToLower(feat.Name))
+	for _, feat := range registry {
+		fmt.Printf("%s\n", strings.ToLower(feat.Name()))
 	}
-	
+
 	return nil
 }
-
@@ -0,0 +1,85 @@
+package lists
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/feat"
+	"github.com/matheusbucater/gmess/internal/feat/lists/listsdb"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type Feature struct{}
+
+func init() {
+	feat.Register(Feature{})
+}
+
+func (Feature) Name() string { return "lists" }
+
+func (Feature) Migrations() fs.FS {
+	m, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil { panic(err) }
+	return m
+}
+
+func (Feature) Cmd(args []string) { Cmd(args) }
+
+// The items are removed by ON DELETE CASCADE, but that would leave a gap in
+// the positions of every list holding the message.
+func (Feature) OnMessageDelete(ctx context.Context, queries *sqlc.Queries, msgId int64) error {
+	lists := listsdb.New(queries.DB())
+	items, err := lists.GetListItemsAndListsByMessageId(ctx, msgId)
+	if err != nil { return err }
+
+	for _, item := range items {
+		if err := removeItemAt(ctx, lists, item.List.ID, item.ListItem.Position); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (Feature) Details(ctx context.Context, queries *sqlc.Queries, msgId int64) (string, error) {
+	items, err := listsdb.New(queries.DB()).GetListItemsAndListsByMessageId(ctx, msgId)
+	if err != nil { return "", err }
+
+	lines := []string{}
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("(%d) %s #%d", item.List.ID, item.List.Name, item.ListItem.Position))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (f Feature) Badge(ctx context.Context, queries *sqlc.Queries, msgId int64, count int64) (string, error) {
+	return feat.DefaultBadge(f.Name()), nil
+}
+
+// Lists holds the names of the lists holding the message.
+func (Feature) Fields(ctx context.Context, queries *sqlc.Queries, msgId int64) (map[string]any, error) {
+	items, err := listsdb.New(queries.DB()).GetListItemsAndListsByMessageId(ctx, msgId)
+	if err != nil { return nil, err }
+
+	lists := []string{}
+	for _, item := range items {
+		lists = append(lists, item.List.Name)
+	}
+	return map[string]any{"Lists": lists}, nil
+}
+
+func (Feature) Counts(ctx context.Context, queries *sqlc.Queries) (map[int64]int64, error) {
+	rows, err := listsdb.New(queries.DB()).CountListItemsByMessage(ctx)
+	if err != nil { return nil, err }
+
+	counts := map[int64]int64{}
+	for _, row := range rows {
+		counts[row.MessageID] = row.Count
+	}
+	return counts, nil
+}
@@ -1,7 +1,386 @@
 package lists
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/feat"
+	"github.com/matheusbucater/gmess/internal/feat/lists/listsdb"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+func showLists() error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+
+	queries := listsdb.New(db)
+
+	lists, err := queries.GetLists(ctx)
+	if err != nil { return err }
+
+	listsCount := len(lists)
+
+	var sb strings.Builder
+	sb.WriteString("You have ")
+	sb.WriteString(strconv.Itoa(listsCount))
+	sb.WriteString(" list")
+
+	if listsCount <= 0 {
+		sb.WriteString("s")
+	} else if listsCount == 1 {
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString("s\n")
+	}
+	fmt.Println(sb.String())
+
+	for _, list := range lists {
+		fmt.Printf("(%d) %s [%d item", list.List.ID, list.List.Name, list.ItemsCount)
+		if list.ItemsCount != 1 { fmt.Print("s") }
+		fmt.Println("]")
+	}
+	return nil
+}
+
+func showList(lstId int64) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+
+	queries := listsdb.New(db)
+
+	exists, err := queries.ListExists(ctx, lstId)
+	if err != nil { return err }
+	if exists != 1 { return errors.New("list does not exist") }
+
+	list, err := queries.GetListById(ctx, lstId)
+	if err != nil { return err }
+
+	items, err := queries.GetListItemsAndMessages(ctx, lstId)
+	if err != nil { return err }
+
+	fmt.Printf("(%d) %s\n", list.ID, list.Name)
+	fmt.Printf("created_at: %s\n", utils.LocalizeDateTime(list.CreatedAt))
+	fmt.Printf("updated_at: %s\n\n", utils.LocalizeDateTime(list.UpdatedAt))
+
+	if len(items) == 0 {
+		fmt.Println("This list is empty")
+		return nil
+	}
+
+	messages := sqlc.New(db)
+	for _, item := range items {
+		badges, err := feat.MessageBadges(ctx, messages, item.Message.ID)
+		if err != nil { return err }
+
+		fmt.Printf("%d. (%d) %s", item.ListItem.Position, item.Message.ID, item.Message.Text)
+		if len(badges) > 0 { fmt.Printf(" [%s]", strings.Join(badges, ", ")) }
+		fmt.Println()
+	}
+	return nil
+}
+
+func createList(name string) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+
+	queries := listsdb.New(db)
+
+	if name == "" { return errors.New("list name can't be empty") }
+
+	exists, err := queries.ListNameExists(ctx, name)
+	if err != nil { return err }
+	if exists == 1 { return fmt.Errorf("list \"%s\" already exists", name) }
+
+	if _, err := queries.CreateList(ctx, name); err != nil { return err }
+	return nil
+}
+
+func renameList(lstId int64, name string) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+
+	queries := listsdb.New(db)
+
+	if name == "" { return errors.New("list name can't be empty") }
+
+	exists, err := queries.ListExists(ctx, lstId)
+	if err != nil { return err }
+	if exists != 1 { return errors.New("list does not exist") }
+
+	exists, err = queries.ListNameExists(ctx, name)
+	if err != nil { return err }
+	if exists == 1 { return fmt.Errorf("list \"%s\" already exists", name) }
+
+	if _, err := queries.RenameList(ctx, listsdb.RenameListParams{ ID: lstId, Name: name }); err != nil { return err }
+	return nil
+}
+
+func deleteList(lstId int64) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+
+	queries := listsdb.New(db)
+
+	exists, err := queries.ListExists(ctx, lstId)
+	if err != nil { return err }
+	if exists != 1 { return errors.New("list does not exist") }
+
+	if err = queries.DeleteListById(ctx, lstId); err != nil { return err }
+	return nil
+}
+
+// addItem inserts the message at the given position, or at the end of the
+// list when position is -1.
+func addItem(lstId int64, msgId int64, position int64) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+
+	queries := listsdb.New(db)
+
+	exists, err := queries.ListExists(ctx, lstId)
+	if err != nil { return err }
+	if exists != 1 { return errors.New("list does not exist") }
+
+	exists, err = sqlc.New(db).MessageExists(ctx, msgId)
+	if err != nil { return err }
+	if exists != 1 { return errors.New("Invalid message ID") }
+
+	exists, err = queries.ListHasMessage(ctx, listsdb.ListHasMessageParams{ ListID: lstId, MessageID: msgId })
+	if err != nil { return err }
+	if exists == 1 { return fmt.Errorf("message (%d) is already in the list", msgId) }
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil { return err }
+	qtx := queries.WithTx(tx)
+
+	count, err := qtx.CountListItems(ctx, lstId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if position == -1 { position = count + 1 }
+	if position < 1 || position > count + 1 {
+		tx.Rollback()
+		return fmt.Errorf("invalid position %d, use 1 to %d", position, count + 1)
+	}
+
+	if err = qtx.ShiftListItems(ctx, listsdb.ShiftListItemsParams{
+		Delta: 1,
+		ListID: lstId,
+		FromPosition: position,
+		ToPosition: count,
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err = qtx.CreateListItem(ctx, listsdb.CreateListItemParams{
+		ListID: lstId,
+		MessageID: msgId,
+		Position: position,
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = qtx.TouchList(ctx, lstId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func removeItem(lstId int64, position int64) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+
+	queries := listsdb.New(db)
+
+	exists, err := queries.ListExists(ctx, lstId)
+	if err != nil { return err }
+	if exists != 1 { return errors.New("list does not exist") }
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil { return err }
+	qtx := queries.WithTx(tx)
+
+	if err = removeItemAt(ctx, qtx, lstId, position); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = qtx.TouchList(ctx, lstId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// removeItemAt deletes the item and closes the gap it leaves behind.
+func removeItemAt(ctx context.Context, queries *listsdb.Queries, lstId int64, position int64) error {
+	count, err := queries.CountListItems(ctx, lstId)
+	if err != nil { return err }
+	if position < 1 || position > count {
+		return fmt.Errorf("invalid position %d, use 1 to %d", position, count)
+	}
+
+	item, err := queries.GetListItemByPosition(ctx, listsdb.GetListItemByPositionParams{ ListID: lstId, Position: position })
+	if err != nil { return err }
+
+	if err = queries.DeleteListItemById(ctx, item.ID); err != nil { return err }
+
+	return queries.ShiftListItems(ctx, listsdb.ShiftListItemsParams{
+		Delta: -1,
+		ListID: lstId,
+		FromPosition: position + 1,
+		ToPosition: count,
+	})
+}
+
+func moveItem(lstId int64, from int64, to int64) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+
+	queries := listsdb.New(db)
+
+	exists, err := queries.ListExists(ctx, lstId)
+	if err != nil { return err }
+	if exists != 1 { return errors.New("list does not exist") }
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil { return err }
+	qtx := queries.WithTx(tx)
+
+	count, err := qtx.CountListItems(ctx, lstId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if from < 1 || from > count || to < 1 || to > count {
+		tx.Rollback()
+		return fmt.Errorf("invalid position, use 1 to %d", count)
+	}
+	if from == to {
+		tx.Rollback()
+		return nil
+	}
+
+	item, err := qtx.GetListItemByPosition(ctx, listsdb.GetListItemByPositionParams{ ListID: lstId, Position: from })
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	shift := listsdb.ShiftListItemsParams{ Delta: 1, ListID: lstId, FromPosition: to, ToPosition: from - 1 }
+	if to > from {
+		shift = listsdb.ShiftListItemsParams{ Delta: -1, ListID: lstId, FromPosition: from + 1, ToPosition: to }
+	}
+	if err = qtx.ShiftListItems(ctx, shift); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = qtx.UpdateListItemPosition(ctx, listsdb.UpdateListItemPositionParams{ ID: item.ID, Position: to }); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = qtx.TouchList(ctx, lstId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
 
 func Cmd(args []string) {
-	fmt.Printf("lists command: %v", args)
+	cmd := flag.NewFlagSet("lists", flag.ExitOnError)
+	actionFlag := cmd.String("a", "r", "action:\n\t\"c\" create,\n\t\"r\" read,\n\t\"u\" update (rename),\n\t\"d\" delete,\n\t\"add\" add message,\n\t\"rm\" remove item,\n\t\"mv\" move item")
+	lstIdFlag := cmd.Int64("lstId", -1, "list id")
+	nameFlag := cmd.String("name", "", "list name")
+	msgIdFlag := cmd.Int64("msgId", -1, "message id")
+	posFlag := cmd.Int64("pos", -1, "item position (starts at 1)")
+	toFlag := cmd.Int64("to", -1, "new item position when moving")
+
+	if err := cmd.Parse(args); err != nil {
+		fmt.Printf("error parsing cli args: %s\n", err)
+		os.Exit(1)
+	}
+
+	switch *actionFlag {
+	case "c":
+		utils.EnforceRequiredFlags(cmd, []string{"name"})
+		if err := createList(*nameFlag); err != nil {
+			fmt.Printf("error creating list: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("list \"%s\" created\n", *nameFlag)
+	case "r":
+		if *lstIdFlag != -1 {
+			if err := showList(*lstIdFlag); err != nil {
+				fmt.Printf("error showing list: %s\n", err)
+				os.Exit(1)
+			}
+		} else {
+			if err := showLists(); err != nil {
+				fmt.Printf("error showing lists: %s\n", err)
+				os.Exit(1)
+			}
+		}
+	case "u":
+		utils.EnforceRequiredFlags(cmd, []string{"lstId", "name"})
+		if err := renameList(*lstIdFlag, *nameFlag); err != nil {
+			fmt.Printf("error renaming list: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("list (%d) renamed\n", *lstIdFlag)
+	case "d":
+		utils.EnforceRequiredFlags(cmd, []string{"lstId"})
+		if err := deleteList(*lstIdFlag); err != nil {
+			fmt.Printf("error deleting list: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("list (%d) deleted\n", *lstIdFlag)
+	case "add":
+		utils.EnforceRequiredFlags(cmd, []string{"lstId", "msgId"})
+		if err := addItem(*lstIdFlag, *msgIdFlag, *posFlag); err != nil {
+			fmt.Printf("error adding message to list: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("message (%d) added to list (%d)\n", *msgIdFlag, *lstIdFlag)
+	case "rm":
+		utils.EnforceRequiredFlags(cmd, []string{"lstId", "pos"})
+		if err := removeItem(*lstIdFlag, *posFlag); err != nil {
+			fmt.Printf("error removing item: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("item %d removed from list (%d)\n", *posFlag, *lstIdFlag)
+	case "mv":
+		utils.EnforceRequiredFlags(cmd, []string{"lstId", "pos", "to"})
+		if err := moveItem(*lstIdFlag, *posFlag, *toFlag); err != nil {
+			fmt.Printf("error moving item: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("item %d moved to %d\n", *posFlag, *toFlag)
+	default:
+		fmt.Printf("invalid action: %s\n", *actionFlag)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,7 @@
+DELETE FROM messages_features WHERE feature_name = 'lists';
+
+DROP TRIGGER IF EXISTS list_items_messages_features_insert;
+DROP TRIGGER IF EXISTS list_items_messages_features_delete;
+
+DROP TABLE IF EXISTS list_items;
+DROP TABLE IF EXISTS lists;
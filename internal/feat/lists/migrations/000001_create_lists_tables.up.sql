@@ -0,0 +1,31 @@
+CREATE TABLE lists (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT UNIQUE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- position starts at 1 and has no UNIQUE constraint so items can be shifted
+-- with a single UPDATE, the lists feature keeps it gapless.
+CREATE TABLE list_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    list_id INTEGER NOT NULL REFERENCES lists(id) ON DELETE CASCADE,
+    message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK(position > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (list_id, message_id)
+);
+
+CREATE TRIGGER list_items_messages_features_insert AFTER INSERT ON list_items
+BEGIN
+    INSERT INTO messages_features (message_id, feature_name, count) VALUES (NEW.message_id, 'lists', 1)
+    ON CONFLICT (message_id, feature_name) DO UPDATE SET count = count + 1;
+END;
+
+CREATE TRIGGER list_items_messages_features_delete AFTER DELETE ON list_items
+BEGIN
+    UPDATE messages_features SET count = count - 1
+    WHERE message_id = OLD.message_id AND feature_name = 'lists';
+    DELETE FROM messages_features
+    WHERE message_id = OLD.message_id AND feature_name = 'lists' AND count <= 0;
+END;
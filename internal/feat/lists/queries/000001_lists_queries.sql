@@ -0,0 +1,82 @@
+-- name: GetLists :many
+SELECT
+    sqlc.embed(lists),
+    (SELECT COUNT(*) FROM list_items WHERE list_items.list_id = lists.id) AS items_count
+FROM lists
+ORDER BY lists.name ASC;
+
+-- name: GetListById :one
+SELECT * FROM lists WHERE id = ?;
+
+-- name: ListExists :one
+SELECT EXISTS(
+    SELECT 1 FROM lists
+    WHERE id = ?
+) AS "exists";
+
+-- name: ListNameExists :one
+SELECT EXISTS(
+    SELECT 1 FROM lists
+    WHERE name = ?
+) AS "exists";
+
+-- name: CreateList :one
+INSERT INTO lists (name) VALUES (?) RETURNING *;
+
+-- name: RenameList :one
+UPDATE lists SET name = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? RETURNING *;
+
+-- name: TouchList :exec
+UPDATE lists SET updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: DeleteListById :exec
+DELETE FROM lists WHERE id = ?;
+
+-- name: CountListItems :one
+SELECT COUNT(*) FROM list_items WHERE list_id = ?;
+
+-- name: GetListItemsAndMessages :many
+SELECT
+    sqlc.embed(list_items),
+    sqlc.embed(messages)
+FROM list_items
+INNER JOIN messages ON messages.id = list_items.message_id
+WHERE list_items.list_id = ?
+ORDER BY list_items.position ASC;
+
+-- name: GetListItemsAndListsByMessageId :many
+SELECT
+    sqlc.embed(list_items),
+    sqlc.embed(lists)
+FROM list_items
+INNER JOIN lists ON lists.id = list_items.list_id
+WHERE list_items.message_id = ?
+ORDER BY lists.name ASC;
+
+-- name: GetListItemByPosition :one
+SELECT * FROM list_items WHERE list_id = ? AND position = ?;
+
+-- name: ListHasMessage :one
+SELECT EXISTS(
+    SELECT 1 FROM list_items
+    WHERE list_id = ?
+    AND message_id = ?
+) AS "exists";
+
+-- name: CreateListItem :one
+INSERT INTO list_items (list_id, message_id, position) VALUES (?, ?, ?) RETURNING *;
+
+-- name: UpdateListItemPosition :exec
+UPDATE list_items SET position = ? WHERE id = ?;
+
+-- name: ShiftListItems :exec
+UPDATE list_items SET position = position + sqlc.arg(delta)
+WHERE list_id = sqlc.arg(list_id)
+AND position >= sqlc.arg(from_position)
+AND position <= sqlc.arg(to_position);
+
+-- name: DeleteListItemById :exec
+DELETE FROM list_items WHERE id = ?;
+
+-- name: CountListItemsByMessage :many
+SELECT message_id, COUNT(*) AS count FROM list_items GROUP BY message_id;
@@ -0,0 +1,305 @@
+package notifications
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+// agendaItem is an occurrence shown by the agenda.
+type agendaItem struct {
+	notification sqlc.Notification
+	message      sqlc.Message
+	at           time.Time
+	note         string
+}
+
+func (i agendaItem) describe(now time.Time) string {
+	line := fmt.Sprintf("(%d) [%s] \"%s\"",
+		i.notification.ID, strings.ToUpper(string(i.notification.Type[0])), i.message.Text)
+	if i.note != "" { line += " (" + i.note + ")" }
+	return line + ", " + countdown(i.at, now)
+}
+
+// countdown says how long until at, e.g. "in 3h12m", "in 2d4h" or "2h ago".
+func countdown(at time.Time, now time.Time) string {
+	if at.Before(now) { return days(now.Sub(at)) + " ago" }
+	return "in " + days(at.Sub(now))
+}
+
+// days is shortDuration counting the days apart.
+func days(d time.Duration) string {
+	if d < 24 * time.Hour { return shortDuration(d) }
+
+	s := fmt.Sprintf("%dd", d / (24 * time.Hour))
+	if rest := shortDuration(d % (24 * time.Hour)); rest != "0s" { s += rest }
+	return s
+}
+
+// unsent returns the occurrences of the notification that are due but have
+// no delivery yet, because nothing ran since, within its remaining
+// occurrences. It also returns how many occurrences are left after them, -1
+// when there's no limit.
+func unsent(ctx context.Context, queries *sqlc.Queries, notification sqlc.Notification, sched schedule, settings sqlc.NotificationSetting, now time.Time) ([]time.Time, int, error) {
+	since, err := dueSince(ctx, queries, notification, settings, now)
+	if err != nil { return nil, 0, err }
+
+	due := []time.Time{}
+	for _, at := range sched.between(since, now) {
+		exists, err := queries.DeliveryExists(ctx, sqlc.DeliveryExistsParams{NotificationID: notification.ID, OccurrenceAt: at.UTC()})
+		if err != nil { return nil, 0, err }
+		if exists != 1 { due = append(due, at) }
+	}
+
+	left, err := remaining(ctx, queries, notification, settings)
+	if err != nil { return nil, 0, err }
+	if left >= 0 {
+		if len(due) > left { due = due[:left] }
+		left -= len(due)
+	}
+	return due, left, nil
+}
+
+// pastDue returns the occurrences that went out, or should have, and still
+// wait for something: to be sent at all, retried, released after quiet
+// hours, repeated until acknowledged or sent again after a snooze. The ones
+// snoozed past now are returned apart, since they're upcoming again.
+func pastDue(ctx context.Context, queries *sqlc.Queries, now time.Time) ([]agendaItem, []agendaItem, error) {
+	deliveries, err := queries.GetUnackedDeliveriesAndMessages(ctx)
+	if err != nil { return nil, nil, err }
+
+	due := []agendaItem{}
+	snoozed := []agendaItem{}
+	for _, d := range deliveries {
+		item := agendaItem{notification: d.Notification, message: d.Message, at: d.Delivery.OccurrenceAt.In(time.Local)}
+
+		switch {
+		case d.Delivery.SnoozedUntil.Valid && d.Delivery.SnoozedUntil.Time.After(now):
+			item.at = d.Delivery.SnoozedUntil.Time.In(time.Local)
+			item.note = fmt.Sprintf("snoozed, from %s", utils.LocalizeDateTime(d.Delivery.OccurrenceAt.In(time.Local)))
+			snoozed = append(snoozed, item)
+			continue
+		case d.Delivery.SnoozedUntil.Valid:
+			item.note = "snoozed"
+		case d.Delivery.NextRepeatAt.Valid:
+			item.note = fmt.Sprintf("unacknowledged, repeating at %s", utils.LocalizeDateTime(d.Delivery.NextRepeatAt.Time.In(time.Local)))
+		case d.Delivery.Outcome == "failed":
+			item.note = fmt.Sprintf("failed (%s)", d.Delivery.Error.String)
+		case d.Delivery.Outcome == "gave_up":
+			item.note = fmt.Sprintf("gave up (%s)", d.Delivery.Error.String)
+		case d.Delivery.Outcome == "held":
+			item.note = "held back during quiet hours"
+		default:
+			item.note = d.Delivery.Outcome
+		}
+		due = append(due, item)
+	}
+
+	notifications, err := queries.GetUnfinishedNotifications(ctx)
+	if err != nil { return nil, nil, err }
+
+	for _, notification := range notifications {
+		sched, err := loadSchedule(ctx, queries, notification)
+		if err != nil { continue } // reported by upcoming
+
+		settings, err := queries.GetNotificationSettingsByNotificationId(ctx, notification.ID)
+		if err != nil { return nil, nil, err }
+		occurrences, _, err := unsent(ctx, queries, notification, sched, settings, now)
+		if err != nil { return nil, nil, err }
+		if len(occurrences) == 0 { continue }
+
+		message, err := queries.GetMessageById(ctx, notification.MessageID)
+		if err != nil { return nil, nil, err }
+
+		for _, at := range occurrences {
+			due = append(due, agendaItem{notification: notification, message: message, at: at.In(time.Local), note: "due, not sent"})
+		}
+	}
+
+	sortAgenda(due)
+	return due, snoozed, nil
+}
+
+// upcoming returns the occurrences of the unfinished notifications in
+// (from, to] still ahead of now, leaving out the skipped dates and the ones
+// past their remaining occurrences.
+func upcoming(ctx context.Context, queries *sqlc.Queries, from time.Time, to time.Time, now time.Time) ([]agendaItem, error) {
+	notifications, err := queries.GetUnfinishedNotifications(ctx)
+	if err != nil { return nil, err }
+
+	if from.Before(now) { from = now }
+
+	items := []agendaItem{}
+	for _, notification := range notifications {
+		sched, err := loadSchedule(ctx, queries, notification)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "skipping notification (%d): %s\n", notification.ID, err)
+			continue
+		}
+
+		settings, err := queries.GetNotificationSettingsByNotificationId(ctx, notification.ID)
+		if err != nil { return nil, err }
+		_, left, err := unsent(ctx, queries, notification, sched, settings, now)
+		if err != nil { return nil, err }
+
+		occurrences := sched.between(from, to)
+		if left >= 0 && len(occurrences) > left {
+			occurrences = occurrences[:left]
+		}
+		if len(occurrences) == 0 { continue }
+
+		message, err := queries.GetMessageById(ctx, notification.MessageID)
+		if err != nil { return nil, err }
+
+		for _, at := range occurrences {
+			items = append(items, agendaItem{notification: notification, message: message, at: at.In(time.Local)})
+		}
+	}
+	return items, nil
+}
+
+// sortAgenda orders the items by time, then by notification.
+func sortAgenda(items []agendaItem) {
+	slices.SortStableFunc(items, func(a agendaItem, b agendaItem) int {
+		if c := a.at.Compare(b.at); c != 0 { return c }
+		return cmp.Compare(a.notification.ID, b.notification.ID)
+	})
+}
+
+// agendaDay holds the items of a day, in order.
+type agendaDay struct {
+	date  time.Time // midnight of the day
+	items []agendaItem
+}
+
+// byDay groups the items, sorted, by the day they fall on.
+func byDay(items []agendaItem) []agendaDay {
+	days := []agendaDay{}
+	for _, item := range items {
+		date := time.Date(item.at.Year(), item.at.Month(), item.at.Day(), 0, 0, 0, 0, item.at.Location())
+		if len(days) == 0 || !days[len(days) - 1].date.Equal(date) {
+			days = append(days, agendaDay{date: date})
+		}
+		days[len(days) - 1].items = append(days[len(days) - 1].items, item)
+	}
+	return days
+}
+
+// agenda returns the past due occurrences and, sorted, the ones in (from, to]
+// including the snoozed ones coming back then.
+func agenda(ctx context.Context, queries *sqlc.Queries, from time.Time, to time.Time, now time.Time) ([]agendaItem, []agendaItem, error) {
+	due, snoozed, err := pastDue(ctx, queries, now)
+	if err != nil { return nil, nil, err }
+
+	items, err := upcoming(ctx, queries, from, to, now)
+	if err != nil { return nil, nil, err }
+	for _, item := range snoozed {
+		if item.at.After(from) && !item.at.After(to) { items = append(items, item) }
+	}
+	sortAgenda(items)
+
+	return due, items, nil
+}
+
+// showAgenda lists the past due occurrences, then the ones in (from, to]
+// grouped by day.
+func showAgenda(from time.Time, to time.Time) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+	defer db.Close()
+
+	queries := sqlc.New(db)
+
+	now := time.Now()
+	due, items, err := agenda(ctx, queries, from, to, now)
+	if err != nil { return err }
+
+	if len(due) > 0 {
+		fmt.Println("Past due")
+		for _, item := range due {
+			fmt.Printf("\t%s %s\n", utils.LocalizeDateTime(item.at), item.describe(now))
+		}
+		fmt.Println()
+	}
+
+	if len(items) == 0 {
+		fmt.Printf("Nothing from %s to %s\n", utils.LocalizeDateTime(from), utils.LocalizeDateTime(to))
+		return nil
+	}
+
+	for i, day := range byDay(items) {
+		if i > 0 { fmt.Println() }
+		fmt.Println(utils.LocalizeDate(day.date))
+		for _, item := range day.items {
+			fmt.Printf("\t%s %s\n", item.at.Format("15:04:05"), item.describe(now))
+		}
+	}
+	return nil
+}
+
+// parseAgendaDate parses "DD/MM/YY HH-MM-SS" or "DD/MM/YY", the latter being
+// the start of the day, or its last instant when end is true.
+func parseAgendaDate(date string, end bool) (time.Time, error) {
+	if t, err := time.ParseInLocation("02/01/06 15-04-05", date, time.Local); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation("02/01/06", date, time.Local)
+	if err != nil { return time.Time{}, err }
+	if end { t = t.AddDate(0, 0, 1).Add(-time.Nanosecond) }
+	return t, nil
+}
+
+func agendaCmd(args []string) {
+	cmd := flag.NewFlagSet("agenda", flag.ExitOnError)
+	fromFlag := cmd.String("from", "", "show the occurrences from\nlayout: DD/MM/YY HH-MM-SS or DD/MM/YY (default now)")
+	toFlag := cmd.String("to", "", "show the occurrences until\nlayout: DD/MM/YY HH-MM-SS or DD/MM/YY, the whole day")
+	daysFlag := cmd.Int("days", 7, "show the occurrences of the next days from -from, unless -to is given")
+
+	if err := cmd.Parse(args); err != nil {
+		fmt.Printf("error parsing cli args: %s\n", err)
+		os.Exit(1)
+	}
+
+	from := time.Now()
+	if *fromFlag != "" {
+		var err error
+		from, err = parseAgendaDate(*fromFlag, false)
+		if err != nil {
+			fmt.Printf("error parsing from date: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *daysFlag <= 0 {
+		fmt.Println("-days must be positive")
+		os.Exit(1)
+	}
+	to := from.AddDate(0, 0, *daysFlag)
+	if *toFlag != "" {
+		var err error
+		to, err = parseAgendaDate(*toFlag, true)
+		if err != nil {
+			fmt.Printf("error parsing to date: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if !to.After(from) {
+		fmt.Println("-to must come after -from")
+		os.Exit(1)
+	}
+
+	if err := showAgenda(from, to); err != nil {
+		fmt.Printf("error showing agenda: %s\n", err)
+		os.Exit(1)
+	}
+}
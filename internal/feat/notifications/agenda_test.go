@@ -0,0 +1,228 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+// agendaStart is a monday.
+var agendaStart = time.Date(2030, 1, 7, 0, 0, 0, 0, time.Local)
+
+// okSink takes every notice.
+type okSink struct{}
+
+func (okSink) send(n notice) error { return nil }
+func (okSink) summary(held []notice) error { return nil }
+func (okSink) close() error { return nil }
+
+// testCreatedAt backdates the notification, which isn't due before it was
+// created.
+func testCreatedAt(t *testing.T, db *sql.DB, queries *sqlc.Queries, notification sqlc.Notification, at time.Time) sqlc.Notification {
+	t.Helper()
+
+	if _, err := db.Exec("UPDATE notifications SET created_at = ? WHERE id = ?", at.UTC(), notification.ID); err != nil { t.Fatal(err) }
+	notification, err := queries.GetNotificationById(context.Background(), notification.ID)
+	if err != nil { t.Fatal(err) }
+	return notification
+}
+
+// testCron creates a cron notification of a new message, created an hour
+// before agendaStart.
+func testCron(t *testing.T, db *sql.DB, queries *sqlc.Queries, text string, expr string) notice {
+	t.Helper()
+	ctx := context.Background()
+
+	message, err := queries.CreateMessage(ctx, text)
+	if err != nil { t.Fatal(err) }
+	notification, err := queries.CreateNotification(ctx, sqlc.CreateNotificationParams{
+		MessageID: message.ID,
+		Type: e_cron_notification.string(),
+	})
+	if err != nil { t.Fatal(err) }
+	if err := queries.CreateCronNotification(ctx, sqlc.CreateCronNotificationParams{
+		NotificationID: notification.ID,
+		Expression: expr,
+	}); err != nil { t.Fatal(err) }
+
+	notification = testCreatedAt(t, db, queries, notification, agendaStart.Add(-time.Hour))
+	return notice{notification: notification, message: message}
+}
+
+// testSimple creates a simple notification of a new message at at, created
+// an hour before agendaStart.
+func testSimple(t *testing.T, db *sql.DB, queries *sqlc.Queries, text string, at time.Time) notice {
+	t.Helper()
+
+	n := testNotice(t, queries, text, at)
+	n.notification = testCreatedAt(t, db, queries, n.notification, agendaStart.Add(-time.Hour))
+	return n
+}
+
+// describeItems renders the items as "DD HH:MM (notification id) note".
+func describeItems(items []agendaItem) []string {
+	lines := []string{}
+	for _, item := range items {
+		line := fmt.Sprintf("%s (%d)", item.at.Format("02 15:04"), item.notification.ID)
+		if item.note != "" { line += " " + item.note }
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestAgendaDueNotSent(t *testing.T) {
+	db, queries := testDb(t)
+	ctx := context.Background()
+	at := agendaStart.Add(-2 * time.Second)
+	n := testSimple(t, db, queries, "buy milk", at)
+
+	due, items, err := agenda(ctx, queries, agendaStart, agendaStart.AddDate(0, 0, 1), agendaStart)
+	if err != nil { t.Fatal(err) }
+	if got := describeItems(due); len(got) != 1 || !due[0].at.Equal(at) || due[0].note != "due, not sent" {
+		t.Errorf("past due = %q, want the occurrence at %s, due and not sent", got, at)
+	}
+	if len(items) != 0 { t.Errorf("upcoming = %q, want none", describeItems(items)) }
+
+	if err := deliver(ctx, queries, okSink{}, n.notification, n.message, at, false); err != nil { t.Fatal(err) }
+
+	due, _, err = agenda(ctx, queries, agendaStart, agendaStart.AddDate(0, 0, 1), agendaStart)
+	if err != nil { t.Fatal(err) }
+	if len(due) != 0 { t.Errorf("past due after the delivery = %q, want none", describeItems(due)) }
+}
+
+func TestAgendaDays(t *testing.T) {
+	db, queries := testDb(t)
+	ctx := context.Background()
+
+	testCron(t, db, queries, "stretch", "0 9,18 * * *")                          // (1)
+	testSimple(t, db, queries, "standup", agendaStart.Add(9 * time.Hour))         // (2)
+	testSimple(t, db, queries, "lunch", agendaStart.Add(36 * time.Hour))          // (3)
+	testSimple(t, db, queries, "too late", agendaStart.AddDate(0, 0, 2))          // (4)
+	testSimple(t, db, queries, "before from", agendaStart.Add(-30 * time.Minute)) // (5)
+
+	to, err := parseAgendaDate("08/01/30", true)
+	if err != nil { t.Fatal(err) }
+	_, items, err := agenda(ctx, queries, agendaStart, to, agendaStart)
+	if err != nil { t.Fatal(err) }
+
+	want := [][]string{
+		{"07 09:00 (1)", "07 09:00 (2)", "07 18:00 (1)"},
+		{"08 09:00 (1)", "08 12:00 (3)", "08 18:00 (1)"},
+	}
+	days := byDay(items)
+	if len(days) != len(want) {
+		t.Fatalf("%d days, want %d: %q", len(days), len(want), describeItems(items))
+	}
+	for i, day := range days {
+		if !day.date.Equal(agendaStart.AddDate(0, 0, i)) { t.Errorf("day %d is %s, want %s", i, day.date, agendaStart.AddDate(0, 0, i)) }
+		if got := describeItems(day.items); strings.Join(got, ", ") != strings.Join(want[i], ", ") {
+			t.Errorf("day %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestAgendaSnoozed(t *testing.T) {
+	db, queries := testDb(t)
+	ctx := context.Background()
+	at := agendaStart.Add(-time.Minute)
+	n := testSimple(t, db, queries, "buy milk", at)
+
+	if err := deliver(ctx, queries, okSink{}, n.notification, n.message, at, false); err != nil { t.Fatal(err) }
+	delivery, err := queries.GetCurrentDelivery(ctx, n.notification.ID)
+	if err != nil { t.Fatal(err) }
+	until := agendaStart.Add(3 * time.Hour)
+	if err := queries.SnoozeDelivery(ctx, sqlc.SnoozeDeliveryParams{
+		SnoozedUntil: sql.NullTime{Time: until.UTC(), Valid: true},
+		ID: delivery.ID,
+	}); err != nil { t.Fatal(err) }
+
+	// it's upcoming again, and only shows up when the range reaches it
+	tests := []struct {
+		to   time.Time
+		want []string
+	}{
+		{agendaStart.Add(2 * time.Hour), []string{}},
+		{agendaStart.Add(3 * time.Hour), []string{"07 03:00 (1) snoozed, from " + utils.LocalizeDateTime(at)}},
+		{agendaStart.AddDate(0, 0, 1), []string{"07 03:00 (1) snoozed, from " + utils.LocalizeDateTime(at)}},
+	}
+	for _, tt := range tests {
+		due, items, err := agenda(ctx, queries, agendaStart, tt.to, agendaStart)
+		if err != nil { t.Fatal(err) }
+		if len(due) != 0 { t.Errorf("to %s: past due = %q, want none", tt.to, describeItems(due)) }
+		if got := describeItems(items); strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+			t.Errorf("to %s: upcoming = %q, want %q", tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestAgendaMaxOccurrences(t *testing.T) {
+	db, queries := testDb(t)
+	ctx := context.Background()
+	n := testCron(t, db, queries, "drink water", "0 * * * *")
+	if err := queries.UpdateNotificationMaxOccurrences(ctx, sqlc.UpdateNotificationMaxOccurrencesParams{
+		MaxOccurrences: sql.NullInt64{Int64: 3, Valid: true},
+		NotificationID: n.notification.ID,
+	}); err != nil { t.Fatal(err) }
+
+	to := agendaStart.AddDate(0, 0, 1)
+	tests := []struct {
+		now      time.Time
+		deliver  []time.Time
+		due      []string
+		upcoming []string
+	}{
+		// the occurrence at 00:00 wasn't sent, so 2 are left
+		{agendaStart, nil, []string{"07 00:00 (1) due, not sent"}, []string{"07 01:00 (1)", "07 02:00 (1)"}},
+		// once it is, still 2 are left
+		{agendaStart, []time.Time{agendaStart}, []string{}, []string{"07 01:00 (1)", "07 02:00 (1)"}},
+		// 01:00 is late and counts too
+		{agendaStart.Add(90 * time.Minute), nil, []string{"07 01:00 (1) due, not sent"}, []string{"07 02:00 (1)"}},
+		{agendaStart.Add(90 * time.Minute), []time.Time{agendaStart.Add(time.Hour), agendaStart.Add(2 * time.Hour)}, []string{}, []string{}},
+	}
+	for i, tt := range tests {
+		for _, at := range tt.deliver {
+			if err := deliver(ctx, queries, okSink{}, n.notification, n.message, at, false); err != nil { t.Fatal(err) }
+		}
+
+		due, items, err := agenda(ctx, queries, agendaStart, to, tt.now)
+		if err != nil { t.Fatal(err) }
+		if got := describeItems(due); strings.Join(got, ", ") != strings.Join(tt.due, ", ") {
+			t.Errorf("%d: past due = %q, want %q", i, got, tt.due)
+		}
+		if got := describeItems(items); strings.Join(got, ", ") != strings.Join(tt.upcoming, ", ") {
+			t.Errorf("%d: upcoming = %q, want %q", i, got, tt.upcoming)
+		}
+	}
+}
+
+func TestParseAgendaDate(t *testing.T) {
+	tests := []struct {
+		date string
+		end  bool
+		want time.Time
+		ok   bool
+	}{
+		{"07/01/30", false, agendaStart, true},
+		{"07/01/30", true, agendaStart.AddDate(0, 0, 1).Add(-time.Nanosecond), true},
+		{"07/01/30 09-30-00", false, agendaStart.Add(9 * time.Hour + 30 * time.Minute), true},
+		{"07/01/30 09-30-00", true, agendaStart.Add(9 * time.Hour + 30 * time.Minute), true},
+		{"31/12/29", true, agendaStart.AddDate(0, 0, -6).Add(-time.Nanosecond), true},
+		{"2030-01-07", false, time.Time{}, false},
+		{"07/01/30 9h", false, time.Time{}, false},
+		{"", false, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, err := parseAgendaDate(tt.date, tt.end)
+		if (err == nil) != tt.ok {
+			t.Errorf("parseAgendaDate(%q, %t) error = %v, want ok %t", tt.date, tt.end, err, tt.ok)
+			continue
+		}
+		if tt.ok && !got.Equal(tt.want) { t.Errorf("parseAgendaDate(%q, %t) = %s, want %s", tt.date, tt.end, got, tt.want) }
+	}
+}
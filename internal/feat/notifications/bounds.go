@@ -0,0 +1,58 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+)
+
+// boundedSchedule drops the occurrences of a schedule that fall outside of
+// the notification's start and end dates.
+type boundedSchedule struct {
+	schedule
+	startsAt sql.NullTime
+	endsAt   sql.NullTime
+}
+
+func (s boundedSchedule) between(from time.Time, to time.Time) []time.Time {
+	if s.startsAt.Valid && s.startsAt.Time.After(from) {
+		// between excludes from, and an occurrence right at the start counts
+		from = s.startsAt.Time.Add(-time.Nanosecond)
+	}
+	if s.endsAt.Valid && s.endsAt.Time.Before(to) {
+		to = s.endsAt.Time
+	}
+	if !to.After(from) { return []time.Time{} }
+
+	return s.schedule.between(from, to)
+}
+
+// remaining returns how many more occurrences the notification may have, or
+// -1 when it has no limit.
+func remaining(ctx context.Context, queries *sqlc.Queries, notification sqlc.Notification, settings sqlc.NotificationSetting) (int, error) {
+	if !settings.MaxOccurrences.Valid { return -1, nil }
+
+	count, err := queries.CountDeliveriesByNotificationId(ctx, notification.ID)
+	if err != nil { return 0, err }
+	return max(int(settings.MaxOccurrences.Int64 - count), 0), nil
+}
+
+// finishIfDone marks the notification finished once it's past its end date
+// or had all of its occurrences. It's kept, along with its history, but
+// notify doesn't look at it again.
+func finishIfDone(ctx context.Context, queries *sqlc.Queries, notification sqlc.Notification, settings sqlc.NotificationSetting, now time.Time) error {
+	done := settings.EndsAt.Valid && !now.Before(settings.EndsAt.Time)
+	if !done {
+		left, err := remaining(ctx, queries, notification, settings)
+		if err != nil { return err }
+		done = left == 0
+	}
+	if !done { return nil }
+
+	return queries.FinishNotification(ctx, sqlc.FinishNotificationParams{
+		FinishedAt: sql.NullTime{Time: now.UTC(), Valid: true},
+		ID: notification.ID,
+	})
+}
@@ -0,0 +1,95 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+)
+
+func TestBoundedBetween(t *testing.T) {
+	day := func(d int) time.Time { return agendaStart.AddDate(0, 0, d).Add(9 * time.Hour) }
+	daily := intervalSchedule{every: 1, unit: "day", anchor: day(0)}
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+
+	tests := []struct {
+		name     string
+		schedule boundedSchedule
+		from     time.Time
+		to       time.Time
+		want     []time.Time
+	}{
+		{"unbounded", boundedSchedule{schedule: daily}, day(0), day(3), []time.Time{day(1), day(2), day(3)}},
+		// occurrences right at the start or at the end count
+		{"start", boundedSchedule{schedule: daily, startsAt: at(day(2))}, day(0), day(3), []time.Time{day(2), day(3)}},
+		{"end", boundedSchedule{schedule: daily, endsAt: at(day(2))}, day(0), day(3), []time.Time{day(1), day(2)}},
+		{"before the end", boundedSchedule{schedule: daily, endsAt: at(day(2).Add(-time.Second))}, day(0), day(3), []time.Time{day(1)}},
+		{"both", boundedSchedule{schedule: daily, startsAt: at(day(1).Add(time.Second)), endsAt: at(day(2))}, day(0), day(3), []time.Time{day(2)}},
+		{"past the end", boundedSchedule{schedule: daily, endsAt: at(day(2))}, day(2), day(5), []time.Time{}},
+		{"before the start", boundedSchedule{schedule: daily, startsAt: at(day(4))}, day(0), day(3), []time.Time{}},
+	}
+
+	for _, tt := range tests {
+		got := tt.schedule.between(tt.from, tt.to)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: between(%s, %s) = %s, want %s", tt.name, tt.from, tt.to, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("%s: between(%s, %s) = %s, want %s", tt.name, tt.from, tt.to, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestMaxOccurrences(t *testing.T) {
+	db, queries := testDb(t)
+	ctx := context.Background()
+	n := testCron(t, db, queries, "drink water", "0 * * * *")
+	settings := sqlc.NotificationSetting{MaxOccurrences: sql.NullInt64{Int64: 2, Valid: true}}
+
+	left, err := remaining(ctx, queries, n.notification, sqlc.NotificationSetting{})
+	if err != nil { t.Fatal(err) }
+	if left != -1 { t.Errorf("remaining without max occurrences = %d, want -1", left) }
+
+	for i, want := range []int{2, 1, 0, 0} {
+		left, err := remaining(ctx, queries, n.notification, settings)
+		if err != nil { t.Fatal(err) }
+		if left != want { t.Errorf("remaining after %d occurrences = %d, want %d", i, left, want) }
+
+		if err := finishIfDone(ctx, queries, n.notification, settings, agendaStart); err != nil { t.Fatal(err) }
+		notification, err := queries.GetNotificationById(ctx, n.notification.ID)
+		if err != nil { t.Fatal(err) }
+		if notification.FinishedAt.Valid != (want == 0) {
+			t.Errorf("finished after %d occurrences = %t, want %t", i, notification.FinishedAt.Valid, want == 0)
+		}
+
+		// a delivery over the limit still counts
+		if err := deliver(ctx, queries, okSink{}, n.notification, n.message, agendaStart.Add(time.Duration(i) * time.Hour), false); err != nil { t.Fatal(err) }
+	}
+}
+
+func TestEndsAt(t *testing.T) {
+	db, queries := testDb(t)
+	ctx := context.Background()
+	n := testCron(t, db, queries, "drink water", "0 * * * *")
+	end := agendaStart.Add(2 * time.Hour)
+	settings := sqlc.NotificationSetting{EndsAt: sql.NullTime{Time: end, Valid: true}}
+
+	for _, tt := range []struct {
+		now  time.Time
+		want bool
+	}{
+		{end.Add(-time.Second), false},
+		{end, true},
+	} {
+		if err := finishIfDone(ctx, queries, n.notification, settings, tt.now); err != nil { t.Fatal(err) }
+		notification, err := queries.GetNotificationById(ctx, n.notification.ID)
+		if err != nil { t.Fatal(err) }
+		if notification.FinishedAt.Valid != tt.want { t.Errorf("finished at %s = %t, want %t", tt.now, notification.FinishedAt.Valid, tt.want) }
+	}
+}
@@ -0,0 +1,173 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/config"
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+)
+
+// router is the sink notify uses when channels are set up in the config
+// file. Each notice goes to every sink of its notification's channel, or to
+// the fallback sink when it has none. A send fails when any of the sinks
+// fails. The sinks it went out to are recorded in delivery_sinks, so retries
+// only go to the ones that failed.
+type router struct {
+	ctx       context.Context
+	queries   *sqlc.Queries
+	config    config.Config
+	profile   string
+	fallback  string                        // spec of the sink of the notices without a channel
+	templates map[string]*template.Template // by channel, "" for the global one
+	opened    map[string]sink               // by spec, opened the first time they're used
+	failed    []sink                        // the sinks that failed the last send
+}
+
+func newRouter(ctx context.Context, queries *sqlc.Queries, c config.Config, profile string, fallbackSpec string, fallback sink, templates map[string]*template.Template) *router {
+	return &router{ctx: ctx, queries: queries, config: c, profile: profile, fallback: fallbackSpec, templates: templates, opened: map[string]sink{fallbackSpec: fallback}}
+}
+
+// channelOf returns the channel of the notification and whether it's its
+// own, or "" when it has none.
+func channelOf(c config.Config, profile string, notification sqlc.Notification, settings sqlc.NotificationSetting) (string, bool) {
+	if settings.Channel.Valid { return settings.Channel.String, true }
+	return c.Route(profile, notification.Type), false
+}
+
+func (r *router) channel(notification sqlc.Notification) (string, error) {
+	settings, err := r.queries.GetNotificationSettingsByNotificationId(r.ctx, notification.ID)
+	if err != nil { return "", err }
+
+	channel, _ := channelOf(r.config, r.profile, notification, settings)
+	return channel, nil
+}
+
+// template returns the template of the channel, or else the global one, for
+// the notices that don't have one of their own.
+func (r *router) template(channel string) *template.Template {
+	if t, ok := r.templates[channel]; ok { return t }
+	return r.templates[""]
+}
+
+// sinks returns the specs of the sinks of the channel, opening them.
+func (r *router) sinks(channel string) ([]string, error) {
+	if channel == "" { return []string{r.fallback}, nil }
+
+	specs, ok := r.config.Channels[channel]
+	if !ok { return nil, fmt.Errorf("channel \"%s\" does not exist", channel) }
+
+	for _, spec := range specs {
+		if _, ok := r.opened[spec]; ok { continue }
+
+		s, err := openSink(spec)
+		if err != nil { return nil, fmt.Errorf("channel \"%s\": %w", channel, err) }
+		r.opened[spec] = s
+	}
+	return specs, nil
+}
+
+// sentTo returns the specs of the sinks the delivery of the notice already
+// went out to. Repeats go out to every sink, every time.
+func (r *router) sentTo(n notice) (map[string]bool, error) {
+	sent := map[string]bool{}
+	if n.deliveryID == 0 || n.repeat > 0 { return sent, nil }
+
+	specs, err := r.queries.GetDeliverySinks(r.ctx, n.deliveryID)
+	if err != nil { return nil, err }
+	for _, spec := range specs {
+		sent[spec] = true
+	}
+	return sent, nil
+}
+
+func (r *router) send(n notice) error {
+	r.failed = nil
+
+	channel, err := r.channel(n.notification)
+	if err != nil { return err }
+	specs, err := r.sinks(channel)
+	if err != nil { return err }
+	if n.template == nil { n.template = r.template(channel) }
+
+	sent, err := r.sentTo(n)
+	if err != nil { return err }
+
+	errs := []error{}
+	for _, spec := range specs {
+		if sent[spec] { continue }
+
+		s := r.opened[spec]
+		if err := s.send(n); err != nil {
+			r.failed = append(r.failed, s)
+			errs = append(errs, err)
+			continue
+		}
+		if n.deliveryID == 0 || n.repeat > 0 { continue }
+		if err := r.queries.AddDeliverySink(r.ctx, sqlc.AddDeliverySinkParams{
+			DeliveryID: n.deliveryID,
+			Sink: spec,
+			SentAt: time.Now().UTC(),
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// summary sends each channel the summary of its own held notices.
+func (r *router) summary(held []notice) error {
+	channels := []string{}
+	byChannel := map[string][]notice{}
+	for _, n := range held {
+		channel, err := r.channel(n.notification)
+		if err != nil { return err }
+		if _, ok := byChannel[channel]; !ok { channels = append(channels, channel) }
+		if n.template == nil { n.template = r.template(channel) }
+		byChannel[channel] = append(byChannel[channel], n)
+	}
+
+	errs := []error{}
+	for _, channel := range channels {
+		specs, err := r.sinks(channel)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, spec := range specs {
+			errs = append(errs, r.opened[spec].summary(byChannel[channel]))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *router) close() error {
+	errs := []error{}
+	for _, s := range r.opened {
+		errs = append(errs, s.close())
+	}
+	return errors.Join(errs...)
+}
+
+// retryAt follows the sinks that failed the last send: the earliest retry
+// any of them asks for, the next run if one of them doesn't space out its
+// retries, or giving up when they all do.
+func (r *router) retryAt(attempts int64, now time.Time) (time.Time, bool) {
+	if len(r.failed) == 0 { return time.Time{}, true }
+
+	var at time.Time
+	retry := false
+	for _, s := range r.failed {
+		rt, ok := s.(retrier)
+		if !ok { return time.Time{}, true }
+
+		t, ok := rt.retryAt(attempts, now)
+		if !ok { continue }
+		if t.IsZero() { return time.Time{}, true }
+		if !retry || t.Before(at) { at, retry = t, true }
+	}
+	return at, retry
+}
@@ -0,0 +1,136 @@
+package notifications
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/config"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+// emailSink sends one email per notification through an SMTP server.
+type emailSink struct {
+	email   config.Email
+	subject *template.Template
+}
+
+// openEmailSink opens the email named name in the config file.
+func openEmailSink(name string) (sink, error) {
+	c, err := config.Load()
+	if err != nil { return nil, err }
+
+	email, ok := c.Emails[name]
+	if !ok { return nil, fmt.Errorf("email \"%s\" is not in the config file", name) }
+
+	subject, err := parseTemplate("subject", email.SubjectOrDefault())
+	if err != nil { return nil, fmt.Errorf("email \"%s\": %w", name, err) }
+
+	return emailSink{email: email, subject: subject}, nil
+}
+
+// message renders the email, headers included.
+func (s emailSink) message(subject string, body string) []byte {
+	// A line break in the subject would start a header of its own.
+	subject = strings.Join(strings.Fields(subject), " ")
+
+	var sb strings.Builder
+	sb.WriteString("From: " + s.email.From + "\r\n")
+	sb.WriteString("To: " + strings.Join(s.email.To, ", ") + "\r\n")
+	sb.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	sb.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	sb.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(strings.TrimRight(body, "\n"), "\n", "\r\n"))
+	sb.WriteString("\r\n")
+	return []byte(sb.String())
+}
+
+// mail sends the email. STARTTLS is used whenever the server offers it.
+func (s emailSink) mail(subject string, body string) error {
+	timeout := s.email.TimeoutOrDefault()
+	addr := s.email.Addr()
+	host, _, _ := net.SplitHostPort(addr)
+
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil { return err }
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil { return err }
+	} else if s.email.RequireTLS {
+		return errors.New("smtp server doesn't support STARTTLS")
+	}
+	if s.email.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.email.Username, s.email.Password, host)); err != nil { return err }
+	}
+
+	from, err := mail.ParseAddress(s.email.From)
+	if err != nil { return err }
+	if err := c.Mail(from.Address); err != nil { return err }
+	for _, to := range s.email.To {
+		rcpt, err := mail.ParseAddress(to)
+		if err != nil { return err }
+		if err := c.Rcpt(rcpt.Address); err != nil { return err }
+	}
+
+	w, err := c.Data()
+	if err != nil { return err }
+	if _, err := w.Write(s.message(subject, body)); err != nil { return err }
+	if err := w.Close(); err != nil { return err }
+
+	return c.Quit()
+}
+
+func (s emailSink) send(n notice) error {
+	now := time.Now()
+	p := n.payload(now)
+
+	var subject strings.Builder
+	if err := s.subject.Execute(&subject, n.templateData(now)); err != nil { return err }
+
+	var body strings.Builder
+	body.WriteString(p.Text + "\n\n")
+	body.WriteString(fmt.Sprintf("notification: %d (%s)\n", p.NotificationID, p.Type))
+	body.WriteString("occurrence: " + utils.LocalizeDateTime(p.OccurrenceAt) + "\n")
+	if p.Repeat > 0 {
+		body.WriteString(fmt.Sprintf("repeat: %d\n", p.Repeat))
+	}
+	return s.mail(subject.String(), body.String())
+}
+
+func (s emailSink) summary(held []notice) error {
+	now := time.Now()
+	p := heldSummary(held)
+
+	var body strings.Builder
+	body.WriteString(p.Text + "\n\n")
+	for _, n := range held {
+		body.WriteString(n.line(now) + "\n")
+	}
+	return s.mail(strings.TrimSuffix(p.Text, ":"), body.String())
+}
+
+func (s emailSink) close() error {
+	return nil
+}
+
+func (s emailSink) retryAt(attempts int64, now time.Time) (time.Time, bool) {
+	return backoff(s.email.Retries, attempts, now)
+}
@@ -0,0 +1,213 @@
+package notifications
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+// fakeSMTP is an SMTP server that takes every mail, without STARTTLS nor
+// AUTH, unless it's told to refuse them.
+type fakeSMTP struct {
+	host  string
+	port  int
+	mails chan string // the data of every mail taken, as sent
+}
+
+// newFakeSMTP starts a fakeSMTP. refuse is its answer to MAIL, "" to take
+// the mails.
+func newFakeSMTP(t *testing.T, refuse string) *fakeSMTP {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil { t.Fatal(err) }
+	t.Cleanup(func() { l.Close() })
+
+	addr := l.Addr().(*net.TCPAddr)
+	s := &fakeSMTP{host: addr.IP.String(), port: addr.Port, mails: make(chan string, 10)}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil { return }
+			go s.serve(conn, refuse)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn, refuse string) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil { return }
+
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(cmd, "MAIL"):
+			if refuse != "" {
+				reply(refuse)
+				continue
+			}
+			reply("250 ok")
+		case strings.HasPrefix(cmd, "RCPT"):
+			reply("250 ok")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil { return }
+				if line == ".\r\n" { break }
+				data.WriteString(line)
+			}
+			s.mails <- data.String()
+			reply("250 ok")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *fakeSMTP) config(extra string) string {
+	return fmt.Sprintf(`{"emails": {"me": {"host": "%s", "port": %d, "from": "gmess <me@example.com>", "to": ["me@example.com", "you@example.com"]%s}}}`,
+		s.host, s.port, extra)
+}
+
+func TestEmailSend(t *testing.T) {
+	_, queries := testDb(t)
+	n := testNotice(t, queries, "café at 9", time.Now())
+
+	srv := newFakeSMTP(t, "")
+	testConfig(t, srv.config(`, "subject": "Reminder: {{.Text}} ({{.Type}})"`))
+	s, err := openEmailSink("me")
+	if err != nil { t.Fatal(err) }
+
+	if err := s.send(n); err != nil { t.Fatal(err) }
+
+	var data string
+	select {
+	case data = <-srv.mails:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail received")
+	}
+
+	if strings.Contains(strings.ReplaceAll(data, "\r\n", ""), "\n") {
+		t.Errorf("mail has bare LF line endings:\n%q", data)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil { t.Fatal(err) }
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil { t.Fatal(err) }
+	if want := "Reminder: café at 9 (simple)"; subject != want { t.Errorf("Subject = %q, want %q", subject, want) }
+	if got := msg.Header.Get("From"); got != "gmess <me@example.com>" { t.Errorf("From = %q", got) }
+	if got := msg.Header.Get("To"); got != "me@example.com, you@example.com" { t.Errorf("To = %q", got) }
+	if got := msg.Header.Get("Content-Type"); got != "text/plain; charset=utf-8" { t.Errorf("Content-Type = %q", got) }
+	if got := msg.Header.Get("MIME-Version"); got != "1.0" { t.Errorf("MIME-Version = %q", got) }
+	if _, err := msg.Header.Date(); err != nil { t.Errorf("Date: %s", err) }
+
+	var body strings.Builder
+	if _, err := bufio.NewReader(msg.Body).WriteTo(&body); err != nil { t.Fatal(err) }
+	if !strings.HasPrefix(body.String(), "café at 9\r\n\r\n") { t.Errorf("body = %q, want it to start with the text", body.String()) }
+	if want := fmt.Sprintf("notification: %d (simple)\r\n", n.notification.ID); !strings.Contains(body.String(), want) {
+		t.Errorf("body = %q, want it to hold %q", body.String(), want)
+	}
+}
+
+func TestEmailSubjectTemplate(t *testing.T) {
+	_, queries := testDb(t)
+	at := time.Now().Add(-2 * time.Hour)
+	n := testNotice(t, queries, "buy milk", at)
+
+	srv := newFakeSMTP(t, "")
+	testConfig(t, srv.config(`, "subject": "{{.Text}}{{if .Late}} ({{duration .Late}} late, at {{datetime .ScheduledAt}}){{end}}"`))
+	s, err := openEmailSink("me")
+	if err != nil { t.Fatal(err) }
+
+	if err := s.send(n); err != nil { t.Fatal(err) }
+
+	var data string
+	select {
+	case data = <-srv.mails:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail received")
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil { t.Fatal(err) }
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil { t.Fatal(err) }
+	if want := "buy milk (2h late, at " + utils.LocalizeDateTime(at.In(time.Local)) + ")"; subject != want {
+		t.Errorf("Subject = %q, want %q", subject, want)
+	}
+}
+
+func TestEmailSubjectInvalid(t *testing.T) {
+	srv := newFakeSMTP(t, "")
+	testConfig(t, srv.config(`, "subject": "{{nope .Text}}"`))
+	if _, err := openEmailSink("me"); err == nil || !strings.Contains(err.Error(), "invalid template") {
+		t.Errorf("openEmailSink = %v, want the subject refused", err)
+	}
+}
+
+func TestEmailRequireTLS(t *testing.T) {
+	_, queries := testDb(t)
+	n := testNotice(t, queries, "buy milk", time.Now())
+
+	srv := newFakeSMTP(t, "")
+	testConfig(t, srv.config(`, "require_tls": true`))
+	s, err := openEmailSink("me")
+	if err != nil { t.Fatal(err) }
+
+	err = s.send(n)
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Fatalf("send = %v, want it refused for the lack of STARTTLS", err)
+	}
+	select {
+	case <-srv.mails:
+		t.Error("mail sent without TLS")
+	default:
+	}
+}
+
+func TestEmailRetry(t *testing.T) {
+	_, queries := testDb(t)
+	ctx := context.Background()
+	at := time.Now().Add(-time.Minute).Truncate(time.Second)
+	n := testNotice(t, queries, "buy milk", at)
+
+	srv := newFakeSMTP(t, "451 try again later")
+	testConfig(t, srv.config(`, "max_attempts": 2, "backoff": "5m"`))
+	s, err := openEmailSink("me")
+	if err != nil { t.Fatal(err) }
+
+	before := time.Now()
+	if err := deliver(ctx, queries, s, n.notification, n.message, at, false); err != nil { t.Fatal(err) }
+
+	delivery, err := queries.GetCurrentDelivery(ctx, n.notification.ID)
+	if err != nil { t.Fatal(err) }
+	if delivery.Outcome != "failed" { t.Fatalf("outcome = %s, want failed", delivery.Outcome) }
+	if !strings.Contains(delivery.Error.String, "451") { t.Errorf("error = %q, want the server's answer", delivery.Error.String) }
+	if !delivery.RetryAt.Valid { t.Fatal("retry_at not set") }
+	if got := delivery.RetryAt.Time.Sub(before); got < 5 * time.Minute - time.Second || got > 5 * time.Minute + time.Second {
+		t.Errorf("retrying %s later, want 5m", got)
+	}
+}
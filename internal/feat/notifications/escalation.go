@@ -0,0 +1,86 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/config"
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+)
+
+// scheduleRepeat sets when the occurrence that was just sent is sent again,
+// if its notification repeats until acknowledged and it has repeats left.
+// repeats is how many times it was repeated so far.
+func scheduleRepeat(ctx context.Context, queries *sqlc.Queries, delivery sqlc.Delivery, repeats int64) error {
+	settings, err := queries.GetNotificationSettingsByNotificationId(ctx, delivery.NotificationID)
+	if err != nil { return err }
+
+	if !settings.RepeatEvery.Valid { return nil }
+	if settings.MaxRepeats.Valid && repeats >= settings.MaxRepeats.Int64 { return nil }
+
+	next := time.Now().UTC().Add(time.Duration(settings.RepeatEvery.Int64) * time.Second)
+	return queries.ScheduleRepeat(ctx, sqlc.ScheduleRepeatParams{
+		NextRepeatAt: sql.NullTime{Time: next, Valid: true},
+		ID: delivery.ID,
+	})
+}
+
+// escalate sends the repeat to the extra sink of its notification.
+func escalate(spec string, n notice) error {
+	s, err := openSink(spec)
+	if err != nil { return err }
+	defer s.close()
+
+	return s.send(n)
+}
+
+// notifyRepeats sends again the unacknowledged occurrences whose repeat is
+// due, to the sink and to the notification's escalation sink if it has one.
+// A repeat counts even if it fails to send. During quiet hours the repeats of
+// notifications that aren't urgent wait for them to be over.
+func notifyRepeats(ctx context.Context, queries *sqlc.Queries, s sink, quiet config.QuietHours, now time.Time) error {
+	deliveries, err := queries.GetDueRepeats(ctx, sql.NullTime{Time: now.UTC(), Valid: true})
+	if err != nil { return err }
+
+	for _, delivery := range deliveries {
+		settings, err := queries.GetNotificationSettingsByNotificationId(ctx, delivery.NotificationID)
+		if err != nil { return err }
+
+		if end, ok := quiet.End(now); ok && !settings.Urgent {
+			if err := queries.ScheduleRepeat(ctx, sqlc.ScheduleRepeatParams{
+				NextRepeatAt: sql.NullTime{Time: end.UTC(), Valid: true},
+				ID: delivery.ID,
+			}); err != nil { return err }
+			continue
+		}
+
+		claimed, err := queries.ClaimRepeat(ctx, sqlc.ClaimRepeatParams{
+			ID: delivery.ID,
+			NextRepeatAt: sql.NullTime{Time: now.UTC(), Valid: true},
+		})
+		if err != nil { return err }
+		if claimed != 1 { continue }
+
+		notification, err := queries.GetNotificationById(ctx, delivery.NotificationID)
+		if err != nil { return err }
+		message, err := queries.GetMessageById(ctx, notification.MessageID)
+		if err != nil { return err }
+
+		n, err := newNotice(ctx, queries, notification, message, delivery.OccurrenceAt, delivery.Repeats + 1)
+		if err != nil { return err }
+		if err := s.send(n); err != nil {
+			fmt.Fprintf(os.Stderr, "error repeating notification (%d): %s\n", notification.ID, err)
+		}
+		if settings.EscalateTo.Valid {
+			if err := escalate(settings.EscalateTo.String, n); err != nil {
+				fmt.Fprintf(os.Stderr, "error escalating notification (%d): %s\n", notification.ID, err)
+			}
+		}
+
+		if err := scheduleRepeat(ctx, queries, delivery, delivery.Repeats + 1); err != nil { return err }
+	}
+	return nil
+}
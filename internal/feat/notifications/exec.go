@@ -0,0 +1,72 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// execTimeout bounds how long the command of an exec sink may run.
+const execTimeout = 30 * time.Second
+
+// execSink runs a shell command for each notification. The notification is
+// given in GMESS_* environment variables and as JSON on stdin.
+type execSink struct {
+	command string
+}
+
+func (s execSink) run(env []string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil { return err }
+
+	ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.command)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = os.Stdout
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s execSink) send(n notice) error {
+	now := time.Now()
+	p := n.payload(now)
+	return s.run([]string{
+		"GMESS_LINE=" + p.Line,
+		"GMESS_TEXT=" + p.Text,
+		"GMESS_MESSAGE_ID=" + strconv.FormatInt(p.MessageID, 10),
+		"GMESS_NOTIFICATION_ID=" + strconv.FormatInt(p.NotificationID, 10),
+		"GMESS_TYPE=" + p.Type,
+		"GMESS_OCCURRENCE_AT=" + p.OccurrenceAt.Format(time.RFC3339),
+		"GMESS_REPEAT=" + strconv.FormatInt(p.Repeat, 10),
+	}, p)
+}
+
+func (s execSink) summary(held []notice) error {
+	p := heldSummary(held)
+	return s.run([]string{
+		"GMESS_LINE=" + p.Text,
+		"GMESS_TEXT=" + p.Text,
+		"GMESS_HELD=" + strconv.Itoa(p.Held),
+	}, p)
+}
+
+func (s execSink) close() error {
+	return nil
+}
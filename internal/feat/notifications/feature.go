@@ -0,0 +1,62 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/feat"
+)
+
+type Feature struct{}
+
+func init() {
+	feat.Register(Feature{})
+}
+
+func (Feature) Name() string { return "notifications" }
+
+// The notifications tables are part of the core migrations.
+func (Feature) Migrations() fs.FS { return nil }
+
+func (Feature) Cmd(args []string) { Cmd(args) }
+
+// Notifications are removed by ON DELETE CASCADE.
+func (Feature) OnMessageDelete(ctx context.Context, queries *sqlc.Queries, msgId int64) error {
+	return nil
+}
+
+func (Feature) Details(ctx context.Context, queries *sqlc.Queries, msgId int64) (string, error) {
+	notifications, err := queries.GetNotificationsByMessageId(ctx, msgId)
+	if err != nil { return "", err }
+
+	lines := []string{}
+	for _, notification := range notifications {
+		description, err := describeNotification(ctx, queries, notification)
+		if err != nil { return "", err }
+
+		lines = append(lines, fmt.Sprintf("(%d) %s", notification.ID, description))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (f Feature) Badge(ctx context.Context, queries *sqlc.Queries, msgId int64, count int64) (string, error) {
+	return feat.DefaultBadge(f.Name()), nil
+}
+
+func (Feature) Fields(ctx context.Context, queries *sqlc.Queries, msgId int64) (map[string]any, error) {
+	return nil, nil
+}
+
+func (Feature) Counts(ctx context.Context, queries *sqlc.Queries) (map[int64]int64, error) {
+	rows, err := queries.CountNotificationsByMessage(ctx)
+	if err != nil { return nil, err }
+
+	counts := map[int64]int64{}
+	for _, row := range rows {
+		counts[row.MessageID] = row.Count
+	}
+	return counts, nil
+}
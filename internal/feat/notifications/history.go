@@ -0,0 +1,106 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+func showHistory(notId int64) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+
+	queries := sqlc.New(db)
+
+	deliveries := []sqlc.GetDeliveriesAndMessagesRow{}
+	if notId != -1 {
+		exists, err := queries.NotificationExists(ctx, notId)
+		if err != nil { return err }
+		if exists != 1 { return errors.New("notification does not exist") }
+
+		rows, err := queries.GetDeliveriesAndMessagesByNotificationId(ctx, notId)
+		if err != nil { return err }
+		for _, row := range rows {
+			deliveries = append(deliveries, sqlc.GetDeliveriesAndMessagesRow(row))
+		}
+	} else {
+		deliveries, err = queries.GetDeliveriesAndMessages(ctx)
+		if err != nil { return err }
+	}
+
+	deliveriesCount := len(deliveries)
+
+	var sb strings.Builder
+	sb.WriteString("You have ")
+	sb.WriteString(strconv.Itoa(deliveriesCount))
+	if deliveriesCount == 1 {
+		sb.WriteString(" delivery\n")
+	} else if deliveriesCount > 1 {
+		sb.WriteString(" deliveries\n")
+	} else {
+		sb.WriteString(" deliveries")
+	}
+	fmt.Println(sb.String())
+
+	for _, d := range deliveries {
+		fmt.Printf("(%d) [%s] \"%s\" at %s: ",
+			d.Notification.ID, strings.ToUpper(string(d.Notification.Type[0])), d.Message.Text,
+			utils.LocalizeDateTime(d.Delivery.OccurrenceAt.In(time.Local)))
+
+		switch d.Delivery.Outcome {
+		case "delivered":
+			fmt.Printf("delivered at %s", utils.LocalizeDateTime(d.Delivery.DeliveredAt.Time.In(time.Local)))
+		case "failed":
+			fmt.Printf("failed after %d attempt", d.Delivery.Attempts)
+			if d.Delivery.Attempts > 1 { fmt.Print("s") }
+			fmt.Printf(" (%s)", d.Delivery.Error.String)
+			if d.Delivery.RetryAt.Valid {
+				fmt.Printf(", retrying at %s", utils.LocalizeDateTime(d.Delivery.RetryAt.Time.In(time.Local)))
+			}
+		case "gave_up":
+			fmt.Printf("gave up after %d attempt", d.Delivery.Attempts)
+			if d.Delivery.Attempts > 1 { fmt.Print("s") }
+			fmt.Printf(" (%s)", d.Delivery.Error.String)
+		case "held":
+			fmt.Print("held back during quiet hours")
+		default:
+			fmt.Print(d.Delivery.Outcome)
+		}
+		if d.Delivery.Repeats > 0 {
+			fmt.Printf(", repeated %d time", d.Delivery.Repeats)
+			if d.Delivery.Repeats > 1 { fmt.Print("s") }
+		}
+		if d.Delivery.AckedAt.Valid {
+			fmt.Printf(", acked at %s", utils.LocalizeDateTime(d.Delivery.AckedAt.Time.In(time.Local)))
+		} else if d.Delivery.SnoozedUntil.Valid {
+			fmt.Printf(", snoozed until %s", utils.LocalizeDateTime(d.Delivery.SnoozedUntil.Time.In(time.Local)))
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func historyCmd(args []string) {
+	cmd := flag.NewFlagSet("history", flag.ExitOnError)
+	notIdFlag := cmd.Int64("notId", -1, "only show the deliveries of this notification")
+
+	if err := cmd.Parse(args); err != nil {
+		fmt.Printf("error parsing cli args: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := showHistory(*notIdFlag); err != nil {
+		fmt.Printf("error showing history: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,224 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+var intervalUnits = map[string]string{"d": "day", "w": "week", "m": "month", "y": "year"}
+
+// parseEvery parses "-every" values like "3d", "2w", "1m" or "1y".
+func parseEvery(every string) (int64, string, error) {
+	if len(every) < 2 {
+		return 0, "", fmt.Errorf("invalid interval \"%s\". Use N followed by d, w, m or y (e.g. \"2w\")", every)
+	}
+	unit, ok := intervalUnits[every[len(every)-1:]]
+	n, err := strconv.ParseInt(every[:len(every)-1], 10, 64)
+	if !ok || err != nil || n < 1 {
+		return 0, "", fmt.Errorf("invalid interval \"%s\". Use N followed by d, w, m or y (e.g. \"2w\")", every)
+	}
+	return n, unit, nil
+}
+
+type intervalSchedule struct {
+	every   int
+	unit    string
+	anchor  time.Time
+	lastDay bool
+}
+
+func newIntervalSchedule(details sqlc.IntervalNotification) intervalSchedule {
+	return intervalSchedule{
+		every: int(details.Every),
+		unit: details.Unit,
+		anchor: details.Anchor.In(time.Local),
+		lastDay: details.LastDay,
+	}
+}
+
+// nth returns the k-th occurrence, the anchor being the 0-th. Days that don't
+// exist in a month (like the 31st) fall on its last day.
+func (s intervalSchedule) nth(k int) time.Time {
+	a := s.anchor
+	switch s.unit {
+	case "day":
+		return time.Date(a.Year(), a.Month(), a.Day() + k * s.every, a.Hour(), a.Minute(), a.Second(), 0, time.Local)
+	case "week":
+		return time.Date(a.Year(), a.Month(), a.Day() + 7 * k * s.every, a.Hour(), a.Minute(), a.Second(), 0, time.Local)
+	}
+
+	months := k * s.every
+	if s.unit == "year" { months *= 12 }
+
+	lastDay := time.Date(a.Year(), a.Month() + time.Month(months) + 1, 0, 0, 0, 0, 0, time.Local).Day()
+	day := min(a.Day(), lastDay)
+	if s.lastDay { day = lastDay }
+	return time.Date(a.Year(), a.Month() + time.Month(months), day, a.Hour(), a.Minute(), a.Second(), 0, time.Local)
+}
+
+func (s intervalSchedule) between(from time.Time, to time.Time) []time.Time {
+	from = from.In(time.Local)
+
+	// Start a step before the one containing from, so nothing is skipped.
+	k := 0
+	switch s.unit {
+	case "day", "week":
+		days := int(dateOf(from).Sub(dateOf(s.anchor)).Hours() / 24)
+		if s.unit == "week" { days /= 7 }
+		k = days / s.every - 1
+	case "month", "year":
+		months := (from.Year() - s.anchor.Year()) * 12 + int(from.Month() - s.anchor.Month())
+		if s.unit == "year" { months /= 12 }
+		k = months / s.every - 1
+	}
+	k = max(k, 0)
+
+	occurrences := []time.Time{}
+	for at := s.nth(k); !at.After(to); at = s.nth(k) {
+		if at.After(from) { occurrences = append(occurrences, at) }
+		k++
+	}
+	return occurrences
+}
+
+// dateOf drops the time and location of t, leaving a date that can be
+// subtracted without DST getting in the way.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n % 100 >= 11 && n % 100 <= 13:
+	case n % 10 == 1:
+		suffix = "st"
+	case n % 10 == 2:
+		suffix = "nd"
+	case n % 10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// describe renders the interval the way describeNotification does, e.g.
+// "every 2 weeks on fridays at 09:00:00" or "on the 15th of every month at
+// 09:00:00".
+func (s intervalSchedule) describe() string {
+	a := s.anchor
+	at := " at " + a.Format("15:04:05")
+
+	switch s.unit {
+	case "day":
+		if s.every == 1 { return "every day" + at }
+		return fmt.Sprintf("every %d days%s", s.every, at)
+	case "week":
+		weekDay := strings.ToLower(a.Weekday().String())
+		if s.every == 1 { return "every " + weekDay + at }
+		return fmt.Sprintf("every %d weeks on %ss%s", s.every, weekDay, at)
+	case "month":
+		day := "the " + ordinal(a.Day())
+		if s.lastDay { day = "the last day" }
+		if s.every == 1 { return "on " + day + " of every month" + at }
+		return fmt.Sprintf("on %s every %d months%s", day, s.every, at)
+	}
+
+	date := a.Format("January ") + strconv.Itoa(a.Day())
+	if s.every == 1 { return "every year on " + date + at }
+	return fmt.Sprintf("every %d years on %s%s", s.every, date, at)
+}
+
+func createIntervalNotification(msgId int64, every int64, unit string, anchor time.Time, lastDay bool, settings notificationSettings) error {
+	if lastDay && unit != "month" { return errors.New("-lastDay only applies to monthly intervals") }
+
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil {
+		return err
+	}
+
+	queries := sqlc.New(db)
+
+	exists, err := queries.MessageExists(ctx, msgId)
+	if (exists == 0) {
+		return errors.New("Invalid message ID")
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	qtx := queries.WithTx(tx)
+
+	notification, err := qtx.CreateNotification(ctx, sqlc.CreateNotificationParams{
+		MessageID: msgId,
+		Type: notificationTypeEnum.string(e_interval_notification),
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = qtx.CreateIntervalNotification(ctx, sqlc.CreateIntervalNotificationParams{
+		NotificationID: notification.ID,
+		Every: every,
+		Unit: unit,
+		Anchor: anchor,
+		LastDay: lastDay,
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = applySettings(ctx, qtx, notification.ID, settings); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// updateInterval changes the given parts of the interval and keeps the rest.
+func updateInterval(ctx context.Context, queries *sqlc.Queries, notId int64, update notificationUpdate) error {
+	triggerAtDLayout := "02/01/06 15-04-05" // "DD/MM/YY HH-MM-SS"
+
+	details, err := queries.GetIntervalNotificationByNotificationId(ctx, notId)
+	if err != nil { return err }
+
+	if update.every != "" {
+		details.Every, details.Unit, err = parseEvery(update.every)
+		if err != nil { return err }
+	}
+	if update.anchor != "" {
+		anchor, err := time.Parse(triggerAtDLayout, update.anchor)
+		if err != nil { return err }
+		details.Anchor = time.Date(
+			anchor.Year(), anchor.Month(), anchor.Day(),
+			anchor.Hour(), anchor.Minute(), anchor.Second(),
+			0, time.Local,
+		)
+	}
+	if update.lastDay != "" {
+		details.LastDay = update.lastDay == "true"
+	}
+	if details.LastDay && details.Unit != "month" { return errors.New("-lastDay only applies to monthly intervals") }
+
+	return queries.UpdateIntervalNotification(ctx, sqlc.UpdateIntervalNotificationParams{
+		Every: details.Every,
+		Unit: details.Unit,
+		Anchor: details.Anchor,
+		LastDay: details.LastDay,
+		NotificationID: notId,
+	})
+}
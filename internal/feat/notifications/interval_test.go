@@ -0,0 +1,97 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIntervalBetween(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name     string
+		schedule intervalSchedule
+		from     time.Time
+		to       time.Time
+		want     []time.Time
+	}{
+		// days that don't exist in a month fall on its last day, and the
+		// next month is back on the anchor's day
+		{
+			"31st", intervalSchedule{every: 1, unit: "month", anchor: date(2030, 1, 31)},
+			date(2030, 1, 1), date(2030, 4, 30),
+			[]time.Time{date(2030, 1, 31), date(2030, 2, 28), date(2030, 3, 31), date(2030, 4, 30)},
+		},
+		{
+			"31st on a leap year", intervalSchedule{every: 1, unit: "month", anchor: date(2032, 1, 31)},
+			date(2032, 1, 31), date(2032, 3, 31),
+			[]time.Time{date(2032, 2, 29), date(2032, 3, 31)},
+		},
+		{
+			"31st a year later", intervalSchedule{every: 1, unit: "month", anchor: date(2030, 1, 31)},
+			date(2031, 1, 31), date(2031, 3, 31),
+			[]time.Time{date(2031, 2, 28), date(2031, 3, 31)},
+		},
+		{
+			"last day", intervalSchedule{every: 1, unit: "month", anchor: date(2030, 1, 30), lastDay: true},
+			date(2030, 1, 1), date(2030, 4, 30),
+			[]time.Time{date(2030, 1, 31), date(2030, 2, 28), date(2030, 3, 31), date(2030, 4, 30)},
+		},
+		{
+			"last day every 2 months", intervalSchedule{every: 2, unit: "month", anchor: date(2031, 12, 15), lastDay: true},
+			date(2031, 12, 31), date(2032, 6, 30),
+			[]time.Time{date(2032, 2, 29), date(2032, 4, 30), date(2032, 6, 30)},
+		},
+
+		// february 29th
+		{
+			"29/02 every month", intervalSchedule{every: 1, unit: "month", anchor: date(2032, 2, 29)},
+			date(2032, 2, 1), date(2032, 4, 30),
+			[]time.Time{date(2032, 2, 29), date(2032, 3, 29), date(2032, 4, 29)},
+		},
+		{
+			"29/02 every year", intervalSchedule{every: 1, unit: "year", anchor: date(2032, 2, 29)},
+			date(2032, 2, 29), date(2036, 12, 31),
+			[]time.Time{date(2033, 2, 28), date(2034, 2, 28), date(2035, 2, 28), date(2036, 2, 29)},
+		},
+
+		// weeks and days across the end of the year
+		{
+			"2w", intervalSchedule{every: 2, unit: "week", anchor: date(2029, 12, 21)},
+			date(2029, 12, 25), date(2030, 1, 31),
+			[]time.Time{date(2030, 1, 4), date(2030, 1, 18)},
+		},
+		{
+			"2w from an occurrence to another", intervalSchedule{every: 2, unit: "week", anchor: date(2029, 12, 21)},
+			date(2030, 1, 4), date(2030, 1, 18),
+			[]time.Time{date(2030, 1, 18)},
+		},
+		{
+			"3d", intervalSchedule{every: 3, unit: "day", anchor: date(2029, 12, 30)},
+			date(2030, 1, 1), date(2030, 1, 8),
+			[]time.Time{date(2030, 1, 2), date(2030, 1, 5), date(2030, 1, 8)},
+		},
+
+		{
+			"before the anchor", intervalSchedule{every: 1, unit: "day", anchor: date(2030, 1, 10)},
+			date(2030, 1, 1), date(2030, 1, 9),
+			[]time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		got := tt.schedule.between(tt.from, tt.to)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: between(%s, %s) = %s, want %s", tt.name, tt.from, tt.to, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("%s: between(%s, %s) = %s, want %s", tt.name, tt.from, tt.to, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -12,8 +12,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/matheusbucater/gmess/internal/config"
 	"github.com/matheusbucater/gmess/internal/db/sqlc"
-	"github.com/matheusbucater/gmess/internal/feat"
 	"github.com/matheusbucater/gmess/internal/utils"
 )
 
@@ -21,84 +21,345 @@ type notificationTypeEnum int
 const (
 	e_simple_notification notificationTypeEnum = iota
 	e_recurring_notification
+	e_multi_notification
+	e_cron_notification
+	e_interval_notification
 )
 var notificationTypeName = map[notificationTypeEnum]string{
 	e_simple_notification:    "simple",
 	e_recurring_notification: "recurring",
+	e_multi_notification:     "multi",
+	e_cron_notification:      "cron",
+	e_interval_notification:  "interval",
 }
 func (nte notificationTypeEnum) string() string {
 	return notificationTypeName[nte]
 }
 
-func notify() error {
-	ctx := context.Background()
-	db, err := utils.DbConnect(ctx)
+// parseTriggerDates parses a ';' separated list of "DD/MM/YY HH-MM-SS" dates
+// in the local timezone.
+func parseTriggerDates(triggerAt string) ([]time.Time, error) {
+	triggerAtDLayout := "02/01/06 15-04-05" // "DD/MM/YY HH-MM-SS"
+
+	dates := []time.Time{}
+	for _, d := range strings.Split(triggerAt, ";") {
+		d = strings.TrimSpace(d)
+		if d == "" { continue }
+
+		date, err := time.Parse(triggerAtDLayout, d)
+		if err != nil { return nil, err }
+		date = time.Date(
+			date.Year(), date.Month(), date.Day(),
+			date.Hour(), date.Minute(), date.Second(),
+			0, time.Local,
+		)
+		if !slices.ContainsFunc(dates, date.Equal) {
+			dates = append(dates, date)
+		}
+	}
+	if len(dates) == 0 { return nil, errors.New("no dates given") }
+
+	return dates, nil
+}
+
+// parseTriggerTimes parses a ';' separated list of "HH-MM-SS" times.
+func parseTriggerTimes(triggerAt string) ([]string, error) {
+	triggerAtTLayout := "15-04-05" // "HH-MM-SS"
+
+	times := []string{}
+	for _, t := range strings.Split(triggerAt, ";") {
+		t = strings.TrimSpace(t)
+		if t == "" { continue }
+
+		parsed, err := time.Parse(triggerAtTLayout, t)
+		if err != nil { return nil, err }
+		t = parsed.Format(triggerAtTLayout)
+		if !slices.Contains(times, t) {
+			times = append(times, t)
+		}
+	}
+	if len(times) == 0 { return nil, errors.New("no times given") }
+
+	return times, nil
+}
+
+func joinTimes(times []sqlc.RecurringNotificationTime) string {
+	var sb strings.Builder
+	for i, t := range times {
+		sb.WriteString(strings.ReplaceAll(t.TriggerAtTime, "-", ":"))
+		if i == len(times) - 2 { sb.WriteString(" and ") }
+		if i < len(times) - 2 { sb.WriteString(", ") }
+	}
+	return sb.String()
+}
+
+func joinDates(dates []sqlc.MultiNotificationDate) string {
+	var sb strings.Builder
+	for i, d := range dates {
+		sb.WriteString(utils.LocalizeDateTime(d.TriggerAt))
+		if i == len(dates) - 2 { sb.WriteString(" and ") }
+		if i < len(dates) - 2 { sb.WriteString(", ") }
+	}
+	return sb.String()
+}
+
+// staleClaim is how long a pending delivery may stay claimed before another
+// run assumes the process that claimed it died and claims it again.
+const staleClaim = 5 * time.Minute
+
+// deliver claims the occurrence in the deliveries table and sends it, so it's
+// sent at most once no matter how many times or by how many processes notify
+// runs. A failed send is recorded and retried by the next run. A held
+// occurrence is only recorded, to be sent when the quiet hours are over.
+func deliver(ctx context.Context, queries *sqlc.Queries, s sink, notification sqlc.Notification, message sqlc.Message, at time.Time, hold bool) error {
+	now := time.Now().UTC()
+	delivery, err := queries.ClaimDelivery(ctx, sqlc.ClaimDeliveryParams{
+		NotificationID: notification.ID,
+		OccurrenceAt: at.UTC(),
+		ClaimedAt: now,
+		StaleBefore: now.Add(-staleClaim),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
-	queries := sqlc.New(db)
+	if hold {
+		return queries.SetDeliveryHeld(ctx, delivery.ID)
+	}
+
+	n, err := newNotice(ctx, queries, notification, message, at, 0)
+	if err != nil { return err }
+	n.deliveryID = delivery.ID
+	if err := s.send(n); err != nil {
+		fmt.Fprintf(os.Stderr, "error delivering notification (%d): %s\n", notification.ID, err)
+		return failDelivery(ctx, queries, s, delivery, err)
+	}
+
+	if err := queries.SetDeliveryDelivered(ctx, sqlc.SetDeliveryDeliveredParams{
+		DeliveredAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ID: delivery.ID,
+	}); err != nil {
+		return err
+	}
+
+	return scheduleRepeat(ctx, queries, delivery, delivery.Repeats)
+}
+
+// failDelivery records that sending the delivery failed. It's retried on the
+// next run, or when and as long as the sink says so.
+func failDelivery(ctx context.Context, queries *sqlc.Queries, s sink, delivery sqlc.Delivery, sendErr error) error {
+	retryAt := sql.NullTime{}
+	if r, ok := s.(retrier); ok {
+		at, retry := r.retryAt(delivery.Attempts, time.Now())
+		if !retry {
+			return queries.SetDeliveryGaveUp(ctx, sqlc.SetDeliveryGaveUpParams{
+				Error: sql.NullString{String: sendErr.Error(), Valid: true},
+				ID: delivery.ID,
+			})
+		}
+		retryAt = sql.NullTime{Time: at.UTC(), Valid: !at.IsZero()}
+	}
+
+	return queries.SetDeliveryFailed(ctx, sqlc.SetDeliveryFailedParams{
+		Error: sql.NullString{String: sendErr.Error(), Valid: true},
+		RetryAt: retryAt,
+		ID: delivery.ID,
+	})
+}
+
+// notify sends the occurrences due by now that weren't handled yet. The ones
+// at or before since were missed (nothing was running when they triggered)
+// and go through the notification's catch-up policy. Failed deliveries due
+// for a retry, and the ones left pending by a process that died, are retried
+// first. Snoozed occurrences are sent again once their time comes.
+// Notifications past their end date or out of occurrences are marked
+// finished and skipped from then on.
+// During quiet hours only urgent notifications are sent, the rest are held
+// back and sent, after a summary, by the first run once they're over.
+// Occurrences of notifications that repeat until acknowledged are sent
+// again when their repeat is due.
+// Notifications whose schedule can't be loaded are reported and skipped.
+func notify(ctx context.Context, queries *sqlc.Queries, s sink, quiet config.QuietHours, since time.Time, now time.Time) error {
+	hold := quiet.Contains(now)
+	if !hold {
+		if err := releaseHeld(ctx, queries, s); err != nil {
+			return err
+		}
+	}
 
-	notifications, err := queries.GetNotifications(ctx)
+	retries, err := queries.GetRetryableDeliveries(ctx, sqlc.GetRetryableDeliveriesParams{
+		RetryBefore: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		StaleBefore: time.Now().UTC().Add(-staleClaim),
+	})
 	if err != nil {
 		return err
 	}
+	for _, delivery := range retries {
+		notification, err := queries.GetNotificationById(ctx, delivery.NotificationID)
+		if err != nil {
+			return err
+		}
+		message, err := queries.GetMessageById(ctx, notification.MessageID)
+		if err != nil {
+			return err
+		}
+		settings, err := queries.GetNotificationSettingsByNotificationId(ctx, notification.ID)
+		if err != nil {
+			return err
+		}
+		if err := deliver(ctx, queries, s, notification, message, delivery.OccurrenceAt, hold && !settings.Urgent); err != nil {
+			return err
+		}
+	}
 
-	if len(notifications) == 0 {
-		return nil
+	notifications, err := queries.GetUnfinishedNotifications(ctx)
+	if err != nil {
+		return err
 	}
-	
-	fmt.Println()
+
 	for _, notification := range notifications {
-		switch notification.Type {
-		case notificationTypeEnum.string(e_simple_notification):
-			notification_details, err := queries.GetSimpleNotificationByNotificationId(ctx, notification.ID)
+		sched, err := loadSchedule(ctx, queries, notification)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "skipping notification (%d): %s\n", notification.ID, err)
+			continue
+		}
+
+		settings, err := queries.GetNotificationSettingsByNotificationId(ctx, notification.ID)
+		if err != nil {
+			return err
+		}
+		from, err := dueSince(ctx, queries, notification, settings, now)
+		if err != nil {
+			return err
+		}
+
+		occurrences := catchUp(settings.CatchUp, sched.between(from, now), since)
+		left, err := remaining(ctx, queries, notification, settings)
+		if err != nil {
+			return err
+		}
+		if left >= 0 && len(occurrences) > left {
+			occurrences = occurrences[:left]
+		}
+
+		if len(occurrences) > 0 {
+			message, err := queries.GetMessageById(ctx, notification.MessageID)
 			if err != nil {
 				return err
 			}
-			if timeDiff := notification_details.TriggerAt.Sub(time.Now()); timeDiff <= 0{
-				message, err := queries.GetMessageById(ctx, notification.MessageID)
-				if err != nil {
+			for _, at := range occurrences {
+				if err := deliver(ctx, queries, s, notification, message, at, hold && !settings.Urgent); err != nil {
 					return err
 				}
-				fmt.Printf("[%s] \"%s\" (%s)\n", strings.ToUpper(string(notification.Type[0])), message.Text, timeDiff.Round(time.Second))
-			}
-		case notificationTypeEnum.string(e_recurring_notification):
-			notification_details, err := queries.GetRecurringNotificationByNotificationId(ctx, notification.ID)
-			if err != nil {
-				return err
-			}
-			exists, err := queries.RecurringNotificationHasDay(ctx, sqlc.RecurringNotificationHasDayParams{
-				RecurringNotificationID: notification.ID,
-				WeekDay: strings.ToLower(time.Now().Weekday().String()),
-			})
-			if err != nil {
-				return err
-			}
-			if exists != 1 {
-				return nil
 			}
+		}
 
-			triggerAt, err := time.Parse("15-04-05", notification_details.TriggerAtTime.String)
-			now := time.Now()
-			triggerAt = time.Date(
-				now.Year(), now.Month(), now.Day(), 
-				triggerAt.Hour(), triggerAt.Minute(), triggerAt.Second(),
-				now.Nanosecond(), now.Location(),
-			)
-			if err != nil {
-				return err
-			}
+		if err := finishIfDone(ctx, queries, notification, settings, now); err != nil {
+			return err
+		}
+	}
 
-			if timeDiff := triggerAt.Sub(time.Now()); timeDiff <= 0 {
-				message, err := queries.GetMessageById(ctx, notification.MessageID)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("[%s] \"%s\" (%s)\n", strings.ToUpper(string(notification.Type[0])), message.Text, timeDiff.Round(time.Second))
-			}
+	if err := notifySnoozed(ctx, queries, s, now, hold); err != nil {
+		return err
+	}
+
+	return notifyRepeats(ctx, queries, s, quiet, now)
+}
+
+// describeNotification renders when the notification triggers, followed by
+// its type, e.g. "at 09:00:00 on mondays and fridays [R]".
+func describeNotification(ctx context.Context, queries *sqlc.Queries, notification sqlc.Notification) (string, error) {
+	var sb strings.Builder
+
+	switch notification.Type {
+	case notificationTypeEnum.string(e_simple_notification):
+		notification_details, err := queries.GetSimpleNotificationByNotificationId(ctx, notification.ID)
+		if err != nil {	return "", err }
+
+		sb.WriteString("at ")
+		sb.WriteString(utils.LocalizeDateTime(notification_details.TriggerAt))
+	case notificationTypeEnum.string(e_recurring_notification):
+		notification_times, err := queries.GetRecurringNotificationTimesByNotificationId(ctx, notification.ID)
+		if err != nil { return "", err }
+
+		sb.WriteString("at ")
+		sb.WriteString(joinTimes(notification_times))
+		sb.WriteString(" on ")
+
+		notification_days, err := queries.GetRecurringNotificationDaysByNotificationId(ctx, notification.ID)
+		if err != nil { return "", err }
+
+		for i, nd := range notification_days {
+			sb.WriteString(nd.WeekDay)
+			sb.WriteString("s")
+			if i == len(notification_days) - 2 { sb.WriteString(" and ") }
+			if i < len(notification_days) - 2 { sb.WriteString(", ") } 
+		}
+	case notificationTypeEnum.string(e_multi_notification):
+		notification_dates, err := queries.GetMultiNotificationDatesByNotificationId(ctx, notification.ID)
+		if err != nil { return "", err }
+
+		sb.WriteString("at ")
+		sb.WriteString(joinDates(notification_dates))
+	case notificationTypeEnum.string(e_cron_notification):
+		notification_details, err := queries.GetCronNotificationByNotificationId(ctx, notification.ID)
+		if err != nil { return "", err }
+
+		sb.WriteString("on \"")
+		sb.WriteString(notification_details.Expression)
+		sb.WriteString("\"")
+
+		if notification.FinishedAt.Valid { break }
+		sched, err := loadSchedule(ctx, queries, notification)
+		if err != nil { return "", err }
+		if next, ok := nextOccurrence(sched, time.Now()); ok {
+			sb.WriteString(", next at ")
+			sb.WriteString(utils.LocalizeDateTime(next))
 		}
+	case notificationTypeEnum.string(e_interval_notification):
+		notification_details, err := queries.GetIntervalNotificationByNotificationId(ctx, notification.ID)
+		if err != nil { return "", err }
+
+		sb.WriteString(newIntervalSchedule(notification_details).describe())
+	}
+
+	sb.WriteString(" [")
+	sb.WriteString(strings.ToUpper(string(notification.Type[0])))
+	sb.WriteString("]")
+
+	return sb.String(), nil
+}
+
+// orderByNextOccurrence sorts the notifications by their next occurrence,
+// the ones that won't trigger again last.
+func orderByNextOccurrence(ctx context.Context, queries *sqlc.Queries, notifications []sqlc.Notification, sort string) error {
+	now := time.Now()
+	next := map[int64]time.Time{}
+	for _, notification := range notifications {
+		if notification.FinishedAt.Valid { continue }
+
+		sched, err := loadSchedule(ctx, queries, notification)
+		if err != nil { return err }
+		if at, ok := nextOccurrence(sched, now); ok { next[notification.ID] = at }
 	}
+
+	slices.SortStableFunc(notifications, func(a sqlc.Notification, b sqlc.Notification) int {
+		aAt, aOk := next[a.ID]
+		bAt, bOk := next[b.ID]
+		switch {
+		case !aOk || !bOk:
+			if aOk == bOk { return 0 }
+			if aOk { return -1 }
+			return 1
+		case sort == "DESC":
+			return bAt.Compare(aAt)
+		default:
+			return aAt.Compare(bAt)
+		}
+	})
 	return nil
 }
 
@@ -129,6 +390,9 @@ func showNotification(order string, sort string) error {
 		} else {
 			notifications, err = queries.GetNotificationsOrderByTypeDESC(ctx)
 		}
+	case "trigger_at":
+		notifications, err = queries.GetNotificationsOrderByCreatedAtASC(ctx)
+		if err == nil { err = orderByNextOccurrence(ctx, queries, notifications, sort) }
 	}
 	if err != nil { return err }
 	
@@ -155,45 +419,12 @@ func showNotification(order string, sort string) error {
 		message, err := queries.GetMessageById(ctx, notification.MessageID)
 		if err != nil { return err }
 
-		var sb strings.Builder
-		sb.WriteString("(")
-		sb.WriteString(fmt.Sprintf("%d", notification.ID))
-		sb.WriteString(") ")
-		sb.WriteString("\"")
-		sb.WriteString(message.Text)
-		sb.WriteString("\"")
-
-		switch notification.Type {
-		case notificationTypeEnum.string(e_simple_notification):
-			notification_details, err := queries.GetSimpleNotificationByNotificationId(ctx, notification.ID)
-			if err != nil {	return err }
-
-			sb.WriteString(" at ")
-			sb.WriteString(utils.LocalizeDateTime(notification_details.TriggerAt))
-		case notificationTypeEnum.string(e_recurring_notification):
-			notification_details, err := queries.GetRecurringNotificationByNotificationId(ctx, notification.ID)
-			if err != nil { return err }
-
-			sb.WriteString(" at ")
-			sb.WriteString(strings.ReplaceAll(notification_details.TriggerAtTime.String, "-", ":"))
-			sb.WriteString(" on ")
-
-			notification_days, err := queries.GetRecurringNotificationDaysByNotificationId(ctx, notification.ID)
-			if err != nil { return err }
-
-			for i, nd := range notification_days {
-				sb.WriteString(nd.WeekDay)
-				sb.WriteString("s")
-				if i == len(notification_days) - 2 { sb.WriteString(" and ") }
-				if i < len(notification_days) - 2 { sb.WriteString(", ") } 
-			}
-		}
+		description, err := describeNotification(ctx, queries, notification)
+		if err != nil { return err }
 
-		sb.WriteString(" [")
-		sb.WriteString(notification.Type[:5])
-		sb.WriteString("]")
+		if notification.FinishedAt.Valid { description += " (finished)" }
 
-		fmt.Println(sb.String())
+		fmt.Printf("(%d) \"%s\" %s\n", notification.ID, message.Text, description)
 	}
 	
 	return nil
@@ -216,6 +447,9 @@ func showNotificationDetails(notId int64) error {
 	notification := notificationAndMessage.Notification
 	message := notificationAndMessage.Message
 
+	settings, err := queries.GetNotificationSettingsByNotificationId(ctx, notification.ID)
+	if err != nil { return err }
+
 	var sb strings.Builder
 	switch notification.Type {
 	case e_simple_notification.string():
@@ -225,11 +459,14 @@ func showNotificationDetails(notId int64) error {
 		sb.WriteString("\t  trigger_at: ")
 		sb.WriteString(utils.LocalizeDateTime(notification_details.TriggerAt))
 	case e_recurring_notification.string():
-		notification_details, err := queries.GetRecurringNotificationByNotificationId(ctx, notification.ID)
+		notification_times, err := queries.GetRecurringNotificationTimesByNotificationId(ctx, notification.ID)
 		if err != nil { return err }
 
-		sb.WriteString("\t  trigger_at_time: ")
-		sb.WriteString(strings.ReplaceAll(notification_details.TriggerAtTime.String, "-", ":"))
+		sb.WriteString("\t  trigger_at_times:")
+		for _, nt := range notification_times {
+			sb.WriteString("\n\t    ")
+			sb.WriteString(strings.ReplaceAll(nt.TriggerAtTime, "-", ":"))
+		}
 		sb.WriteString("\n")
 
 		notification_days, err := queries.GetRecurringNotificationDaysByNotificationId(ctx, notification.ID)
@@ -242,6 +479,42 @@ func showNotificationDetails(notId int64) error {
 			if i == len(notification_days) - 2 { sb.WriteString(" and ") }
 			if i < len(notification_days) - 2 { sb.WriteString(", ") } 
 		}
+	case e_multi_notification.string():
+		notification_dates, err := queries.GetMultiNotificationDatesByNotificationId(ctx, notification.ID)
+		if err != nil { return err }
+
+		sb.WriteString("\t  trigger_at:")
+		for _, nd := range notification_dates {
+			sb.WriteString("\n\t    ")
+			sb.WriteString(utils.LocalizeDateTime(nd.TriggerAt))
+		}
+	case e_cron_notification.string():
+		notification_details, err := queries.GetCronNotificationByNotificationId(ctx, notification.ID)
+		if err != nil { return err }
+
+		sb.WriteString("\t  expression: ")
+		sb.WriteString(notification_details.Expression)
+
+		if !notification.FinishedAt.Valid {
+			sched, err := loadSchedule(ctx, queries, notification)
+			if err != nil { return err }
+			if next, ok := nextOccurrence(sched, time.Now()); ok {
+				sb.WriteString("\n\t  next_at: ")
+				sb.WriteString(utils.LocalizeDateTime(next))
+			}
+		}
+	case e_interval_notification.string():
+		notification_details, err := queries.GetIntervalNotificationByNotificationId(ctx, notification.ID)
+		if err != nil { return err }
+
+		sb.WriteString(fmt.Sprintf("\t  every: %d %s", notification_details.Every, notification_details.Unit))
+		if notification_details.Every != 1 { sb.WriteString("s") }
+		sb.WriteString("\n")
+		sb.WriteString("\t  anchor: ")
+		sb.WriteString(utils.LocalizeDateTime(notification_details.Anchor.In(time.Local)))
+		if notification_details.LastDay {
+			sb.WriteString("\n\t  last_day: true")
+		}
 	}
 
 	fmt.Println("Notification details:")
@@ -253,13 +526,69 @@ func showNotificationDetails(notId int64) error {
 	fmt.Printf("\t  updated_at: %s\n", utils.LocalizeDateTime(message.UpdatedAt))
 	fmt.Printf("\ttype: %s\n", notification.Type)
 	fmt.Println(sb.String())
+	fmt.Printf("\tcatch_up: %s\n", settings.CatchUp)
+	if settings.MaxLateness.Valid {
+		fmt.Printf("\tmax_lateness: %s\n", time.Duration(settings.MaxLateness.Int64) * time.Second)
+	}
+	if settings.StartsAt.Valid {
+		fmt.Printf("\tstarts_at: %s\n", utils.LocalizeDateTime(settings.StartsAt.Time.In(time.Local)))
+	}
+	if settings.EndsAt.Valid {
+		fmt.Printf("\tends_at: %s\n", utils.LocalizeDateTime(settings.EndsAt.Time.In(time.Local)))
+	}
+	if settings.MaxOccurrences.Valid {
+		count, err := queries.CountDeliveriesByNotificationId(ctx, notification.ID)
+		if err != nil { return err }
+		fmt.Printf("\tmax_occurrences: %d (%d so far)\n", settings.MaxOccurrences.Int64, count)
+	}
+	skipDates, err := queries.GetNotificationSkipDates(ctx, notification.ID)
+	if err != nil { return err }
+	if len(skipDates) > 0 {
+		fmt.Printf("\tskip_dates:\n")
+		for _, d := range skipDates {
+			fmt.Printf("\t  %s\n", localizeDate(d))
+		}
+	}
+	if settings.Calendar.Valid {
+		fmt.Printf("\tcalendar: %s\n", settings.Calendar.String)
+	}
+	if settings.ShiftSkipped {
+		fmt.Printf("\tshift_skipped: true\n")
+	}
+	if settings.Urgent {
+		fmt.Printf("\turgent: true\n")
+	}
+	if settings.RepeatEvery.Valid {
+		fmt.Printf("\trepeat_every: %s\n", time.Duration(settings.RepeatEvery.Int64) * time.Second)
+	}
+	if settings.MaxRepeats.Valid {
+		fmt.Printf("\tmax_repeats: %d\n", settings.MaxRepeats.Int64)
+	}
+	if settings.EscalateTo.Valid {
+		fmt.Printf("\tescalate_to: %s\n", settings.EscalateTo.String)
+	}
+	c, err := config.Load()
+	if err != nil { return err }
+	if channel, own := channelOf(c, utils.Profile(), notification, settings); channel != "" {
+		if own {
+			fmt.Printf("\tchannel: %s\n", channel)
+		} else {
+			fmt.Printf("\tchannel: %s (routed)\n", channel)
+		}
+	}
+	if settings.Template.Valid {
+		fmt.Printf("\ttemplate: %s\n", settings.Template.String)
+	}
+	if notification.FinishedAt.Valid {
+		fmt.Printf("\tfinished_at: %s\n", utils.LocalizeDateTime(notification.FinishedAt.Time.In(time.Local)))
+	}
 	fmt.Printf("\tcreated_at: %s\n", utils.LocalizeDateTime(notification.CreatedAt))
 	fmt.Printf("\tupdated_at: %s\n", utils.LocalizeDateTime(notification.UpdatedAt))
 
 	return nil
 }
 
-func createSimpleNotification (msgId int64, triggerAt time.Time) error {
+func createSimpleNotification (msgId int64, triggerAt time.Time, settings notificationSettings) error {
 	ctx := context.Background()
 	db, err := utils.DbConnect(ctx)
 	if err != nil {
@@ -296,30 +625,73 @@ func createSimpleNotification (msgId int64, triggerAt time.Time) error {
 		return err
 	}
 
-	exists, err = qtx.MessageHasFeature(ctx, sqlc.MessageHasFeatureParams{
+	if err = applySettings(ctx, qtx, notification.ID, settings); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func createRecurringNotification(msgId int64, weekDays []time.Weekday, times []string, settings notificationSettings) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil {
+		return err
+	}
+
+	queries := sqlc.New(db)
+
+	exists, err := queries.MessageExists(ctx, msgId)
+	if (exists == 0) {
+		return errors.New("Invalid message ID")
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	qtx := queries.WithTx(tx)
+
+	notification, err := qtx.CreateNotification(ctx, sqlc.CreateNotificationParams{
 		MessageID: msgId,
-		FeatureName: feat.E_notifications_feature.String(), 
+		Type: notificationTypeEnum.string(e_recurring_notification),
 	})
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	if exists == 1 {
-		if err := qtx.IncrementMessageFeatureCount(ctx, sqlc.IncrementMessageFeatureCountParams{
-			MessageID: msgId,
-			FeatureName: feat.E_notifications_feature.String(), 
-		}); err != nil { return err }
+	if err = qtx.CreateRecurringNotification(ctx, notification.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
 
-		if err := tx.Commit(); err != nil { return err }
+	for _, t := range times {
+		if err = qtx.CreateRecurringNotificationTime(ctx, sqlc.CreateRecurringNotificationTimeParams{
+			RecurringNotificationID: notification.ID,
+			TriggerAtTime: t,
+		}); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
 
-		return nil
+	for _, wd := range weekDays {
+		if err = qtx.CreateRecurringNotificationDay(ctx, sqlc.CreateRecurringNotificationDayParams{
+			RecurringNotificationID: notification.ID,
+			WeekDay: strings.ToLower(wd.String()),
+		}); err != nil {
+			tx.Rollback()
+			return err
+		}
 	}
 
-	if err = qtx.CreateMessageFeature(ctx, sqlc.CreateMessageFeatureParams{
-		MessageID: msgId,
-		FeatureName: feat.E_notifications_feature.String(), 
-	}); err != nil {
+	if err = applySettings(ctx, qtx, notification.ID, settings); err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -331,7 +703,7 @@ func createSimpleNotification (msgId int64, triggerAt time.Time) error {
 	return nil
 }
 
-func createRecurringNotification(msgId int64, weekDays []time.Weekday, triggerAt time.Time) error {
+func createMultiNotification(msgId int64, dates []time.Time, settings notificationSettings) error {
 	ctx := context.Background()
 	db, err := utils.DbConnect(ctx)
 	if err != nil {
@@ -353,60 +725,93 @@ func createRecurringNotification(msgId int64, weekDays []time.Weekday, triggerAt
 
 	notification, err := qtx.CreateNotification(ctx, sqlc.CreateNotificationParams{
 		MessageID: msgId,
-		Type: notificationTypeEnum.string(e_recurring_notification),
+		Type: notificationTypeEnum.string(e_multi_notification),
 	})
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	recurring_notification, err := qtx.CreateRecurringNotification(ctx, sqlc.CreateRecurringNotificationParams{
-		NotificationID: notification.ID,
-		TriggerAtTime: sql.NullString{String: triggerAt.Format("15-04-05"), Valid: true},
-	})
-	if err != nil {
+	if err = qtx.CreateMultiNotification(ctx, notification.ID); err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	for _, wd := range weekDays {
-		if err = qtx.CreateRecurringNotificationDay(ctx, sqlc.CreateRecurringNotificationDayParams{
-			RecurringNotificationID: recurring_notification.NotificationID,
-			WeekDay: strings.ToLower(wd.String()),
+	for _, d := range dates {
+		if err = qtx.CreateMultiNotificationDate(ctx, sqlc.CreateMultiNotificationDateParams{
+			MultiNotificationID: notification.ID,
+			TriggerAt: d,
 		}); err != nil {
 			tx.Rollback()
 			return err
 		}
 	}
 
-	exists, err = qtx.MessageHasFeature(ctx, sqlc.MessageHasFeatureParams{
-		MessageID: msgId,
-		FeatureName: feat.E_notifications_feature.String(), 
-	})
-	if err != nil {
+	if err = applySettings(ctx, qtx, notification.ID, settings); err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	if exists == 1 {
-		if err := qtx.IncrementMessageFeatureCount(ctx, sqlc.IncrementMessageFeatureCountParams{
-			MessageID: msgId,
-			FeatureName: feat.E_notifications_feature.String(), 
-		}); err != nil { return err }
+	if err := tx.Commit(); err != nil {
+		return err
+	}
 
-		if err := tx.Commit(); err != nil { return err }
+	return nil
+}
 
-		return nil
+// parseCron parses the cron expression of a notification, refusing the ones
+// that never match, like "0 0 30 feb *".
+func parseCron(expr string) (utils.Cron, error) {
+	cron, err := utils.ParseCron(expr)
+	if err != nil { return utils.Cron{}, err }
+	if _, ok := cron.Next(time.Now()); !ok {
+		return utils.Cron{}, fmt.Errorf("cron expression \"%s\" never matches", cron)
+	}
+	return cron, nil
+}
+
+func createCronNotification(msgId int64, cron utils.Cron, settings notificationSettings) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil {
+		return err
+	}
+
+	queries := sqlc.New(db)
+
+	exists, err := queries.MessageExists(ctx, msgId)
+	if (exists == 0) {
+		return errors.New("Invalid message ID")
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
 	}
+	qtx := queries.WithTx(tx)
 
-	if err = qtx.CreateMessageFeature(ctx, sqlc.CreateMessageFeatureParams{
+	notification, err := qtx.CreateNotification(ctx, sqlc.CreateNotificationParams{
 		MessageID: msgId,
-		FeatureName: feat.E_notifications_feature.String(), 
+		Type: notificationTypeEnum.string(e_cron_notification),
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = qtx.CreateCronNotification(ctx, sqlc.CreateCronNotificationParams{
+		NotificationID: notification.ID,
+		Expression: cron.String(),
 	}); err != nil {
 		tx.Rollback()
 		return err
 	}
 
+	if err = applySettings(ctx, qtx, notification.ID, settings); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return err
 	}
@@ -414,10 +819,178 @@ func createRecurringNotification(msgId int64, weekDays []time.Weekday, triggerAt
 	return nil
 }
 
-func updateNotification(notId int64, triggerAt string, weekDays string) error {
-	triggerAtDLayout := "02/01/06 15-04-05" // "DD/MM/YY HH-MM-SS"
-	triggerAtTLayout := "15-04-05" // "HH-MM-SS"
+// updateMultiNotificationDates replaces every date of the notification when
+// triggerAt is given, then adds and removes the individual dates. The
+// notification must be left with at least one date.
+func updateMultiNotificationDates(ctx context.Context, queries *sqlc.Queries, notId int64, triggerAt string, addDates string, rmDates string) error {
+	if triggerAt != "" {
+		dates, err := parseTriggerDates(triggerAt)
+		if err != nil { return err }
+		if err = queries.DeleteMultiNotificationDatesByNotificationId(ctx, notId); err != nil { return err }
+		for _, d := range dates {
+			if err = queries.CreateMultiNotificationDate(ctx, sqlc.CreateMultiNotificationDateParams{
+				MultiNotificationID: notId,
+				TriggerAt: d,
+			}); err != nil { return err }
+		}
+	}
+
+	if addDates != "" {
+		dates, err := parseTriggerDates(addDates)
+		if err != nil { return err }
+		for _, d := range dates {
+			exists, err := queries.MultiNotificationHasDate(ctx, sqlc.MultiNotificationHasDateParams{
+				MultiNotificationID: notId,
+				TriggerAt: d,
+			})
+			if err != nil { return err }
+			if exists == 1 { continue }
+
+			if err = queries.CreateMultiNotificationDate(ctx, sqlc.CreateMultiNotificationDateParams{
+				MultiNotificationID: notId,
+				TriggerAt: d,
+			}); err != nil { return err }
+		}
+	}
+
+	if rmDates != "" {
+		dates, err := parseTriggerDates(rmDates)
+		if err != nil { return err }
+		for _, d := range dates {
+			exists, err := queries.MultiNotificationHasDate(ctx, sqlc.MultiNotificationHasDateParams{
+				MultiNotificationID: notId,
+				TriggerAt: d,
+			})
+			if err != nil { return err }
+			if exists != 1 {
+				return fmt.Errorf("notification has no date %s", utils.LocalizeDateTime(d))
+			}
+
+			if err = queries.DeleteMultiNotificationDate(ctx, sqlc.DeleteMultiNotificationDateParams{
+				MultiNotificationID: notId,
+				TriggerAt: d,
+			}); err != nil { return err }
+		}
+	}
+
+	count, err := queries.CountMultiNotificationDates(ctx, notId)
+	if err != nil { return err }
+	if count == 0 {
+		return errors.New("multi notifications need at least one date")
+	}
 
+	return nil
+}
+
+// updateRecurringNotificationTimes replaces every time of the notification
+// when triggerAt is given, then adds and removes the individual times. The
+// notification must be left with at least one time.
+func updateRecurringNotificationTimes(ctx context.Context, queries *sqlc.Queries, notId int64, triggerAt string, addTimes string, rmTimes string) error {
+	if triggerAt != "" {
+		times, err := parseTriggerTimes(triggerAt)
+		if err != nil { return err }
+		if err = queries.DeleteRecurringNotificationTimesByNotificationId(ctx, notId); err != nil { return err }
+		for _, t := range times {
+			if err = queries.CreateRecurringNotificationTime(ctx, sqlc.CreateRecurringNotificationTimeParams{
+				RecurringNotificationID: notId,
+				TriggerAtTime: t,
+			}); err != nil { return err }
+		}
+	}
+
+	if addTimes != "" {
+		times, err := parseTriggerTimes(addTimes)
+		if err != nil { return err }
+		for _, t := range times {
+			exists, err := queries.RecurringNotificationHasTime(ctx, sqlc.RecurringNotificationHasTimeParams{
+				RecurringNotificationID: notId,
+				TriggerAtTime: t,
+			})
+			if err != nil { return err }
+			if exists == 1 { continue }
+
+			if err = queries.CreateRecurringNotificationTime(ctx, sqlc.CreateRecurringNotificationTimeParams{
+				RecurringNotificationID: notId,
+				TriggerAtTime: t,
+			}); err != nil { return err }
+		}
+	}
+
+	if rmTimes != "" {
+		times, err := parseTriggerTimes(rmTimes)
+		if err != nil { return err }
+		for _, t := range times {
+			exists, err := queries.RecurringNotificationHasTime(ctx, sqlc.RecurringNotificationHasTimeParams{
+				RecurringNotificationID: notId,
+				TriggerAtTime: t,
+			})
+			if err != nil { return err }
+			if exists != 1 {
+				return fmt.Errorf("notification has no time %s", strings.ReplaceAll(t, "-", ":"))
+			}
+
+			if err = queries.DeleteRecurringNotificationTime(ctx, sqlc.DeleteRecurringNotificationTimeParams{
+				RecurringNotificationID: notId,
+				TriggerAtTime: t,
+			}); err != nil { return err }
+		}
+	}
+
+	count, err := queries.CountRecurringNotificationTimes(ctx, notId)
+	if err != nil { return err }
+	if count == 0 {
+		return errors.New("recurring notifications need at least one time")
+	}
+
+	return nil
+}
+
+// notificationUpdate holds the flags given to "-a u". Empty fields were not
+// given.
+type notificationUpdate struct {
+	triggerAt string
+	weekDays  string
+	addDates  string
+	rmDates   string
+	addTimes  string
+	rmTimes   string
+	cron      string
+	every     string
+	anchor    string
+	lastDay   string // "true" or "false"
+}
+
+func (u notificationUpdate) flags() [][2]string {
+	return [][2]string{
+		{"triggerAt", u.triggerAt},
+		{"weekDays", u.weekDays},
+		{"addDate", u.addDates},
+		{"rmDate", u.rmDates},
+		{"addTime", u.addTimes},
+		{"rmTime", u.rmTimes},
+		{"cron", u.cron},
+		{"every", u.every},
+		{"anchor", u.anchor},
+		{"lastDay", u.lastDay},
+	}
+}
+
+func (u notificationUpdate) empty() bool {
+	return !slices.ContainsFunc(u.flags(), func(f [2]string) bool { return f[1] != "" })
+}
+
+// check fails when a flag that doesn't apply to the notification type was
+// given.
+func (u notificationUpdate) check(notificationType string, allowed ...string) error {
+	for _, f := range u.flags() {
+		if f[1] != "" && !slices.Contains(allowed, f[0]) {
+			return fmt.Errorf("Invalid flag -%s for %s notifications.", f[0], notificationType)
+		}
+	}
+	return nil
+}
+
+func updateNotification(notId int64, update notificationUpdate, settings notificationSettings) error {
 	ctx := context.Background()
 	db, err := utils.DbConnect(ctx)
 	if err != nil {
@@ -433,11 +1006,37 @@ func updateNotification(notId int64, triggerAt string, weekDays string) error {
 	notification, err := queries.GetNotificationById(ctx, notId)
 	if err != nil { return err }
 	
+	if update.empty() && settings.empty() { return errors.New("Nothing to update.") }
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil { return err }
+	qtx := queries.WithTx(tx)
+
+	if err := updateNotificationDetails(ctx, qtx, notification, update); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := applySettings(ctx, qtx, notId, settings); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// updateNotificationDetails applies the given flags to the details of the
+// notification's type.
+func updateNotificationDetails(ctx context.Context, queries *sqlc.Queries, notification sqlc.Notification, update notificationUpdate) error {
+	triggerAtDLayout := "02/01/06 15-04-05" // "DD/MM/YY HH-MM-SS"
+	notId := notification.ID
+
+	if update.empty() { return nil }
+
 	switch notification.Type {
 	case e_simple_notification.string():
-		if weekDays != "" { return errors.New("Invalid flag -weekDays for simple notifications.") }
+		if err := update.check(notification.Type, "triggerAt"); err != nil { return err }
 
-		triggerAt, err := time.Parse(triggerAtDLayout, triggerAt)
+		triggerAt, err := time.Parse(triggerAtDLayout, update.triggerAt)
 		if err != nil { return err }
 		triggerAt = time.Date(
 			triggerAt.Year(), triggerAt.Month(), triggerAt.Day(), 
@@ -450,19 +1049,15 @@ func updateNotification(notId int64, triggerAt string, weekDays string) error {
 			TriggerAt: triggerAt,
 		}); err != nil { return err }
 	case e_recurring_notification.string():
-		// TODO: allow user to update only the weekdays without having to pass the triggerAt time
-		triggerAt, err := time.Parse(triggerAtTLayout, triggerAt)
-		if err != nil { return err }
+		if err := update.check(notification.Type, "triggerAt", "weekDays", "addTime", "rmTime"); err != nil { return err }
 
-		if weekDays == "" {
-			if _, err = queries.UpdateRecurringNotification(ctx, sqlc.UpdateRecurringNotificationParams{
-				NotificationID: notId,
-				TriggerAtTime: sql.NullString{String: triggerAt.Format("15-04-05"), Valid: true},
-			}); err != nil {
+		if update.triggerAt != "" || update.addTimes != "" || update.rmTimes != "" {
+			if err := updateRecurringNotificationTimes(ctx, queries, notId, update.triggerAt, update.addTimes, update.rmTimes); err != nil {
 				return err
 			}
-		} else {
-			weekDays, err := utils.ParseWeekDays(weekDays)
+		}
+		if update.weekDays != "" {
+			weekDays, err := utils.ParseWeekDays(update.weekDays)
 			if err != nil { return err }
 
 			for _, wd := range []time.Weekday{
@@ -491,6 +1086,26 @@ func updateNotification(notId int64, triggerAt string, weekDays string) error {
 				}
 			}
 		}
+	case e_multi_notification.string():
+		if err := update.check(notification.Type, "triggerAt", "addDate", "rmDate"); err != nil { return err }
+
+		if err := updateMultiNotificationDates(ctx, queries, notId, update.triggerAt, update.addDates, update.rmDates); err != nil {
+			return err
+		}
+	case e_cron_notification.string():
+		if err := update.check(notification.Type, "cron"); err != nil { return err }
+
+		cron, err := parseCron(update.cron)
+		if err != nil { return err }
+
+		if err := queries.UpdateCronNotification(ctx, sqlc.UpdateCronNotificationParams{
+			Expression: cron.String(),
+			NotificationID: notId,
+		}); err != nil { return err }
+	case e_interval_notification.string():
+		if err := update.check(notification.Type, "every", "anchor", "lastDay"); err != nil { return err }
+
+		if err := updateInterval(ctx, queries, notId, update); err != nil { return err }
 	}
 
 	return nil
@@ -509,52 +1124,146 @@ func deleteNotification(notId int64) error {
 	if err != nil { return err }
 	if exists != 1 { return errors.New("notification does not exist") }
 
-	msgId, err := queries.DeleteNotificationByIdReturningMsgId(ctx, notId)
-	if err = queries.DecrementMessageFeatureCount(ctx, sqlc.DecrementMessageFeatureCountParams{
-		MessageID: msgId,
-		FeatureName: feat.E_notifications_feature.String(), 
-	}); err != nil {
-		return err
-	}
+	if err = queries.DeleteNotificationById(ctx, notId); err != nil { return err }
 
 	return nil
 }
 
 func Cmd(args []string) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "watch":
+			watchCmd(args[1:])
+			return
+		case "check":
+			checkCmd(args[1:])
+			return
+		case "history":
+			historyCmd(args[1:])
+			return
+		case "snooze":
+			snoozeCmd(args[1:])
+			return
+		case "ack":
+			ackCmd(args[1:])
+			return
+		case "calendar":
+			calendarCmd(args[1:])
+			return
+		case "agenda":
+			agendaCmd(args[1:])
+			return
+		}
+	}
+
 	triggerAtDLayout := "02/01/06 15-04-05" // "DD/MM/YY HH-MM-SS"
-	triggerAtTLayout := "15-04-05" // "HH-MM-SS"
 
 	cmd := flag.NewFlagSet("notif", flag.ExitOnError)
 	actionFlag := cmd.String("a", "r", "action:\n\t\"c\" create,\n\t\"r\" read,\n\t\"u\" update,\n\t\"d\" delete")
 	recurringFlag := cmd.Bool("recur", false, "use recurring notification type")
+	multiFlag := cmd.Bool("multi", false, "use multi notification type")
+	cronFlag := cmd.String("cron", "", "use cron notification type with this 5-field expression\n(e.g. \"*/30 9-17 * * mon-fri\", \"0 9 * * mon#1\")")
+	everyFlag := cmd.String("every", "", "use interval notification type, repeating every N days, weeks, months or years\n(e.g. \"3d\", \"2w\", \"1m\", \"1y\")")
+	anchorFlag := cmd.String("anchor", "", "first occurrence of an interval notification\nlayout: DD/MM/YY HH-MM-SS")
+	lastDayFlag := cmd.Bool("lastDay", false, "trigger a monthly interval notification on the last day of the month")
 	msgIdFlag := cmd.Int64("msgId", -1, "message id")
-	triggerAtFlag := cmd.String("triggerAt", "", "trigger notification at\nlayout: DD/MM/YY HH-MM-SS or HH-MM-SS")
+	triggerAtFlag := cmd.String("triggerAt", "", "trigger notification at\nlayout: DD/MM/YY HH-MM-SS or HH-MM-SS\n(recur: \"HH-MM-SS;HH-MM-SS;...\")\n(multi: \"DD/MM/YY HH-MM-SS;DD/MM/YY HH-MM-SS;...\")")
+	addDateFlag := cmd.String("addDate", "", "dates to add to a multi notification\n(\"DD/MM/YY HH-MM-SS;...\")")
+	rmDateFlag := cmd.String("rmDate", "", "dates to remove from a multi notification\n(\"DD/MM/YY HH-MM-SS;...\")")
+	addTimeFlag := cmd.String("addTime", "", "times to add to a recurring notification\n(\"HH-MM-SS;...\")")
+	rmTimeFlag := cmd.String("rmTime", "", "times to remove from a recurring notification\n(\"HH-MM-SS;...\")")
 	weekDaysFlag := cmd.String("weekDays", "", "week days that trigger the notification\n(su,mo,tu,we,th,fr,sa)")
 	notIdFlag := cmd.Int64("notId", -1, "notification id")
-	// TODO: add support to order by trigger_at
-	orderFlag := cmd.String("order", "created_at", "order by: 'created_at', 'updated_at' or 'type'")
+	orderFlag := cmd.String("order", "created_at", "order by: 'created_at', 'updated_at', 'type' or 'trigger_at' (the next occurrence)")
 	descFlag := cmd.Bool("desc", false, "retrieve notifications in descending order")
+	addSettingsFlags(cmd)
 
 	if err := cmd.Parse(args); err != nil {
 		fmt.Printf("error parsing cli args: %s\n", err)
 		os.Exit(1)
 	}
 
+	settings, err := settingsFromFlags(cmd)
+	if err != nil {
+		fmt.Println(err)
+		cmd.Usage()
+		os.Exit(1)
+	}
+
+	lastDay := ""
+	cmd.Visit(func(f *flag.Flag) {
+		if f.Name == "lastDay" { lastDay = f.Value.String() }
+	})
+
 	switch *actionFlag {
 	case "c":
-		if *recurringFlag == true {
+		types := 0
+		for _, given := range []bool{*recurringFlag, *multiFlag, *cronFlag != "", *everyFlag != ""} {
+			if given { types++ }
+		}
+		if types > 1 {
+			fmt.Println("flags -recur, -multi, -cron and -every can't be used together")
+			os.Exit(1)
+		}
+		if *everyFlag != "" {
+			utils.EnforceRequiredFlags(cmd, []string{"msgId", "anchor"})
+			every, unit, err := parseEvery(*everyFlag)
+			if err != nil {
+				fmt.Printf("error parsing every: %s\n", err)
+				os.Exit(1)
+			}
+			anchor, err := time.Parse(triggerAtDLayout, *anchorFlag)
+			if err != nil {
+				fmt.Printf("error parsing anchor date: %s\n", err)
+				os.Exit(1)
+			}
+			anchor = time.Date(
+				anchor.Year(), anchor.Month(), anchor.Day(),
+				anchor.Hour(), anchor.Minute(), anchor.Second(),
+				0, time.Local,
+			)
+			if err = createIntervalNotification(*msgIdFlag, every, unit, anchor, *lastDayFlag, settings); err != nil {
+				fmt.Printf("error creating notification: %s\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		} else if *cronFlag != "" {
+			utils.EnforceRequiredFlags(cmd, []string{"msgId"})
+			cron, err := parseCron(*cronFlag)
+			if err != nil {
+				fmt.Printf("error parsing cron expression: %s\n", err)
+				os.Exit(1)
+			}
+			if err = createCronNotification(*msgIdFlag, cron, settings); err != nil {
+				fmt.Printf("error creating notification: %s\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		} else if *multiFlag == true {
+			utils.EnforceRequiredFlags(cmd, []string{"msgId", "triggerAt"})
+			dates, err := parseTriggerDates(*triggerAtFlag)
+			if err != nil {
+				fmt.Printf("error parsing triggerAt dates: %s\n", err)
+				os.Exit(1)
+			}
+			if err = createMultiNotification(*msgIdFlag, dates, settings); err != nil {
+				fmt.Printf("error creating notification: %s\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		} else if *recurringFlag == true {
 			utils.EnforceRequiredFlags(cmd, []string{"msgId", "weekDays", "triggerAt"})
 			weekDays, err := utils.ParseWeekDays(*weekDaysFlag)
 			if err != nil {
 				fmt.Printf("errror parsing weekDays: %s\n", err)
 				os.Exit(1)
 			}
-			triggerAt, err := time.Parse(triggerAtTLayout, *triggerAtFlag)
+			times, err := parseTriggerTimes(*triggerAtFlag)
 			if err != nil {
-				fmt.Printf("errror parsing triggerAtT date: %s\n", err)
+				fmt.Printf("error parsing triggerAt times: %s\n", err)
 				os.Exit(1)
 			}
-			if err = createRecurringNotification(*msgIdFlag, weekDays, triggerAt); err != nil {
+			if err = createRecurringNotification(*msgIdFlag, weekDays, times, settings); err != nil {
 				fmt.Printf("error creating notification: %s\n", err)
 				os.Exit(1)
 			}
@@ -567,7 +1276,7 @@ func Cmd(args []string) {
 				triggerAt.Hour(), triggerAt.Minute(), triggerAt.Second(),
 				0, time.Local,
 			)
-			if err = createSimpleNotification(*msgIdFlag, triggerAt); err != nil {
+			if err = createSimpleNotification(*msgIdFlag, triggerAt, settings); err != nil {
 				fmt.Printf("error creating notification: %s\n", err)
 				os.Exit(1)
 			}
@@ -585,7 +1294,7 @@ func Cmd(args []string) {
 				sort = "DESC"
 			}
 
-			if !slices.Contains([]string{"created_at", "updated_at", "type"}, strings.ToLower(*orderFlag)) {
+			if !slices.Contains([]string{"created_at", "updated_at", "type", "trigger_at"}, strings.ToLower(*orderFlag)) {
 				fmt.Println("invalid value for '-order' flag")
 				cmd.Usage()
 				os.Exit(1)
@@ -596,14 +1305,19 @@ func Cmd(args []string) {
 			}
 		}
 	case "u":
-		utils.EnforceRequiredFlags(cmd, []string{"notId", "triggerAt"})
-		triggerAt, err := time.Parse(triggerAtDLayout, *triggerAtFlag)
-		triggerAt = time.Date(
-			triggerAt.Year(), triggerAt.Month(), triggerAt.Day(), 
-			triggerAt.Hour(), triggerAt.Minute(), triggerAt.Second(),
-			0, time.Local,
-		)
-		if err = updateNotification(*notIdFlag, *triggerAtFlag, *weekDaysFlag); err != nil {
+		utils.EnforceRequiredFlags(cmd, []string{"notId"})
+		if err := updateNotification(*notIdFlag, notificationUpdate{
+			triggerAt: *triggerAtFlag,
+			weekDays: *weekDaysFlag,
+			addDates: *addDateFlag,
+			rmDates: *rmDateFlag,
+			addTimes: *addTimeFlag,
+			rmTimes: *rmTimeFlag,
+			cron: *cronFlag,
+			every: *everyFlag,
+			anchor: *anchorFlag,
+			lastDay: lastDay,
+		}, settings); err != nil {
 			fmt.Printf("errror updating notification: %s\n", err)
 			os.Exit(1)
 		}
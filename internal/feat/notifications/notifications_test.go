@@ -0,0 +1,58 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/utils"
+
+	_ "modernc.org/sqlite"
+)
+
+// testDb opens a new database in a temporary dir, every migration applied.
+func testDb(t *testing.T) (*sql.DB, *sqlc.Queries) {
+	t.Helper()
+
+	utils.SetDbPath(filepath.Join(t.TempDir(), "messages.db"))
+	db, err := utils.DbConnect(context.Background())
+	if err != nil { t.Fatal(err) }
+	t.Cleanup(func() { db.Close() })
+
+	return db, sqlc.New(db)
+}
+
+// testNotice creates a message and a simple notification of it, and returns
+// its notice for at.
+func testNotice(t *testing.T, queries *sqlc.Queries, text string, at time.Time) notice {
+	t.Helper()
+	ctx := context.Background()
+
+	message, err := queries.CreateMessage(ctx, text)
+	if err != nil { t.Fatal(err) }
+	notification, err := queries.CreateNotification(ctx, sqlc.CreateNotificationParams{
+		MessageID: message.ID,
+		Type: e_simple_notification.string(),
+	})
+	if err != nil { t.Fatal(err) }
+	if err := queries.CreateSimpleNotification(ctx, sqlc.CreateSimpleNotificationParams{
+		NotificationID: notification.ID,
+		TriggerAt: at.UTC(),
+	}); err != nil { t.Fatal(err) }
+
+	return notice{notification: notification, message: message, at: at}
+}
+
+// testConfig makes the config file read by config.Load hold config.
+func testConfig(t *testing.T, config string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "gmess"), 0755); err != nil { t.Fatal(err) }
+	if err := os.WriteFile(filepath.Join(dir, "gmess", "config.json"), []byte(config), 0644); err != nil { t.Fatal(err) }
+}
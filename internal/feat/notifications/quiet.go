@@ -0,0 +1,61 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+)
+
+// releaseHeld sends the occurrences held back during quiet hours, after a
+// summary of them.
+func releaseHeld(ctx context.Context, queries *sqlc.Queries, s sink) error {
+	deliveries, err := queries.GetHeldDeliveries(ctx)
+	if err != nil { return err }
+
+	held := []notice{}
+	claimed := []sqlc.Delivery{}
+	for _, delivery := range deliveries {
+		count, err := queries.ClaimHeldDelivery(ctx, sqlc.ClaimHeldDeliveryParams{
+			ClaimedAt: time.Now().UTC(),
+			ID: delivery.ID,
+		})
+		if err != nil { return err }
+		if count != 1 { continue }
+
+		notification, err := queries.GetNotificationById(ctx, delivery.NotificationID)
+		if err != nil { return err }
+		message, err := queries.GetMessageById(ctx, notification.MessageID)
+		if err != nil { return err }
+
+		n, err := newNotice(ctx, queries, notification, message, delivery.OccurrenceAt, 0)
+		if err != nil { return err }
+		n.deliveryID = delivery.ID
+		held = append(held, n)
+		claimed = append(claimed, delivery)
+	}
+	if len(held) == 0 { return nil }
+
+	if err := s.summary(held); err != nil {
+		fmt.Fprintf(os.Stderr, "error summarizing held notifications: %s\n", err)
+	}
+
+	for i, n := range held {
+		if err := s.send(n); err != nil {
+			fmt.Fprintf(os.Stderr, "error delivering notification (%d): %s\n", n.notification.ID, err)
+			if err := failDelivery(ctx, queries, s, claimed[i], err); err != nil { return err }
+			continue
+		}
+
+		if err := queries.SetDeliveryDelivered(ctx, sqlc.SetDeliveryDeliveredParams{
+			DeliveredAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+			ID: claimed[i].ID,
+		}); err != nil { return err }
+
+		if err := scheduleRepeat(ctx, queries, claimed[i], claimed[i].Repeats); err != nil { return err }
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+// schedule tells when a notification triggers.
+type schedule interface {
+	// between returns the trigger times in (from, to], sorted.
+	between(from time.Time, to time.Time) []time.Time
+}
+
+type datesSchedule []time.Time
+
+func (s datesSchedule) between(from time.Time, to time.Time) []time.Time {
+	occurrences := []time.Time{}
+	for _, d := range s {
+		if d.After(from) && !d.After(to) {
+			occurrences = append(occurrences, d)
+		}
+	}
+	slices.SortFunc(occurrences, func(a, b time.Time) int { return a.Compare(b) })
+	return occurrences
+}
+
+type weeklySchedule struct {
+	weekDays []time.Weekday
+	times    []time.Time // only the time of day is used
+}
+
+func (s weeklySchedule) between(from time.Time, to time.Time) []time.Time {
+	occurrences := []time.Time{}
+	from, to = from.In(time.Local), to.In(time.Local)
+
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	for !day.After(to) {
+		if slices.Contains(s.weekDays, day.Weekday()) {
+			for _, t := range s.times {
+				at := time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local)
+				if at.After(from) && !at.After(to) {
+					occurrences = append(occurrences, at)
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return occurrences
+}
+
+type cronSchedule struct {
+	cron utils.Cron
+}
+
+func (s cronSchedule) between(from time.Time, to time.Time) []time.Time {
+	occurrences := []time.Time{}
+	at, ok := s.cron.Next(from.In(time.Local))
+	for ok && !at.After(to) {
+		occurrences = append(occurrences, at)
+		at, ok = s.cron.Next(at)
+	}
+	return occurrences
+}
+
+// nextOccurrence returns the first occurrence after now, looking up to five
+// years ahead in growing windows so dense schedules stay cheap.
+func nextOccurrence(sched schedule, now time.Time) (time.Time, bool) {
+	limit := now.AddDate(5, 0, 0)
+	from, window := now, 24 * time.Hour
+	for from.Before(limit) {
+		if occurrences := sched.between(from, from.Add(window)); len(occurrences) > 0 {
+			return occurrences[0], true
+		}
+		from, window = from.Add(window), window * 2
+	}
+	return time.Time{}, false
+}
+
+// loadSchedule returns the schedule of the notification without its skipped
+// dates, bounded by its start and end dates.
+func loadSchedule(ctx context.Context, queries *sqlc.Queries, notification sqlc.Notification) (schedule, error) {
+	sched, err := typeSchedule(ctx, queries, notification)
+	if err != nil { return nil, err }
+
+	settings, err := queries.GetNotificationSettingsByNotificationId(ctx, notification.ID)
+	if err != nil { return nil, err }
+
+	skipDates, err := loadSkipDates(ctx, queries, notification.ID, settings)
+	if err != nil { return nil, err }
+	if len(skipDates) > 0 {
+		sched = exceptSchedule{schedule: sched, dates: skipDates, shift: settings.ShiftSkipped}
+	}
+
+	if !settings.StartsAt.Valid && !settings.EndsAt.Valid { return sched, nil }
+
+	return boundedSchedule{schedule: sched, startsAt: settings.StartsAt, endsAt: settings.EndsAt}, nil
+}
+
+func typeSchedule(ctx context.Context, queries *sqlc.Queries, notification sqlc.Notification) (schedule, error) {
+	switch notification.Type {
+	case e_simple_notification.string():
+		notification_details, err := queries.GetSimpleNotificationByNotificationId(ctx, notification.ID)
+		if err != nil { return nil, err }
+
+		return datesSchedule{notification_details.TriggerAt}, nil
+	case e_recurring_notification.string():
+		notification_times, err := queries.GetRecurringNotificationTimesByNotificationId(ctx, notification.ID)
+		if err != nil { return nil, err }
+
+		times := []time.Time{}
+		for _, nt := range notification_times {
+			t, err := time.Parse("15-04-05", nt.TriggerAtTime)
+			if err != nil { return nil, err }
+			times = append(times, t)
+		}
+
+		notification_days, err := queries.GetRecurringNotificationDaysByNotificationId(ctx, notification.ID)
+		if err != nil { return nil, err }
+
+		weekDays := []time.Weekday{}
+		for _, nd := range notification_days {
+			for wd := time.Sunday; wd <= time.Saturday; wd++ {
+				if strings.ToLower(wd.String()) == nd.WeekDay { weekDays = append(weekDays, wd) }
+			}
+		}
+
+		return weeklySchedule{weekDays: weekDays, times: times}, nil
+	case e_multi_notification.string():
+		notification_dates, err := queries.GetMultiNotificationDatesByNotificationId(ctx, notification.ID)
+		if err != nil { return nil, err }
+
+		dates := datesSchedule{}
+		for _, nd := range notification_dates {
+			dates = append(dates, nd.TriggerAt)
+		}
+		return dates, nil
+	case e_cron_notification.string():
+		notification_details, err := queries.GetCronNotificationByNotificationId(ctx, notification.ID)
+		if err != nil { return nil, err }
+
+		cron, err := utils.ParseCron(notification_details.Expression)
+		if err != nil { return nil, err }
+
+		return cronSchedule{cron}, nil
+	case e_interval_notification.string():
+		notification_details, err := queries.GetIntervalNotificationByNotificationId(ctx, notification.ID)
+		if err != nil { return nil, err }
+
+		return newIntervalSchedule(notification_details), nil
+	}
+
+	return nil, fmt.Errorf("unknown notification type \"%s\"", notification.Type)
+}
@@ -0,0 +1,327 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/config"
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+)
+
+var catchUpPolicies = []string{"all", "latest", "skip"}
+
+// notificationSettings holds the settings given on the command line. Nil
+// fields were not given and are left untouched.
+type notificationSettings struct {
+	catchUp        *string
+	maxLateness    *time.Duration // 0 removes the limit
+	startsAt       *time.Time     // the zero time removes the bound
+	endsAt         *time.Time     // the zero time removes the bound
+	maxOccurrences *int64         // 0 removes the limit
+	skip           []string       // dates to skip, as stored
+	unskip         []string       // dates not to skip anymore, as stored
+	calendar       *string        // "" removes the calendar
+	shift          *bool
+	urgent         *bool
+	repeatEvery    *time.Duration // 0 stops repeating
+	maxRepeats     *int64         // 0 removes the limit
+	escalateTo     *string        // "" removes the sink
+	channel        *string        // "" removes the channel
+	template       *string        // "" removes the template
+}
+
+func addSettingsFlags(cmd *flag.FlagSet) {
+	cmd.String("catchUp", "latest", "what to do with occurrences missed while nothing was running:\n\t\"all\" send every one,\n\t\"latest\" send only the latest,\n\t\"skip\" send none")
+	cmd.Duration("maxLateness", 0, "occurrences missed for longer than this are dropped (e.g. 2h)\n0 means no limit")
+	cmd.String("startAt", "", "no occurrences before this date\nlayout: DD/MM/YY HH-MM-SS (\"\" removes it)")
+	cmd.String("endAt", "", "no occurrences after this date, the notification is finished then\nlayout: DD/MM/YY HH-MM-SS (\"\" removes it)")
+	cmd.Int64("maxOccurrences", 0, "finish the notification after this many occurrences\n0 means no limit")
+	cmd.String("skip", "", "dates the notification doesn't trigger on\n(\"DD/MM/YY;DD/MM/YY;...\")")
+	cmd.String("unskip", "", "skipped dates to trigger on again\n(\"DD/MM/YY;DD/MM/YY;...\")")
+	cmd.String("calendar", "", "skip the dates of this holiday calendar (\"\" removes it)\nsee \"notifications calendar\"")
+	cmd.Bool("shift", false, "move occurrences on skipped dates to the next business day instead of dropping them")
+	cmd.Bool("urgent", false, "send the notification during quiet hours too")
+	cmd.Duration("repeatEvery", 0, "send occurrences again this often until they're acknowledged (e.g. 10m)\n0 means no repeats")
+	cmd.Int64("maxRepeats", 0, "repeat an occurrence at most this many times\n0 means no limit")
+	cmd.String("template", "", "render the notification with this text/template, e.g.\n\"{{.Text}} ({{duration .Late}} late)\" (\"\" removes it)")
+	cmd.String("channel", "", "send the notification to this channel of the config file (\"\" removes it)")
+	cmd.String("escalateTo", "", "also send repeats to this sink: " + sinkUsage + "\n(\"\" removes it)")
+}
+
+// parseBound parses the value of -startAt or -endAt. An empty value gives
+// the zero time, which removes the bound.
+func parseBound(bound string) (time.Time, error) {
+	triggerAtDLayout := "02/01/06 15-04-05" // "DD/MM/YY HH-MM-SS"
+
+	if bound == "" { return time.Time{}, nil }
+
+	t, err := time.Parse(triggerAtDLayout, bound)
+	if err != nil { return time.Time{}, err }
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local), nil
+}
+
+// settingsFromFlags reads the flags added by addSettingsFlags, keeping only
+// the ones given explicitly.
+func settingsFromFlags(cmd *flag.FlagSet) (notificationSettings, error) {
+	settings := notificationSettings{}
+
+	var err error
+	cmd.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "catchUp":
+			catchUp := f.Value.String()
+			if !slices.Contains(catchUpPolicies, catchUp) {
+				err = fmt.Errorf("invalid value for '-catchUp' flag \"%s\"", catchUp)
+			}
+			settings.catchUp = &catchUp
+		case "maxLateness":
+			maxLateness := f.Value.(flag.Getter).Get().(time.Duration)
+			if maxLateness < 0 {
+				err = fmt.Errorf("invalid value for '-maxLateness' flag \"%s\"", maxLateness)
+			}
+			settings.maxLateness = &maxLateness
+		case "startAt", "endAt":
+			bound, parseErr := parseBound(f.Value.String())
+			if parseErr != nil {
+				err = fmt.Errorf("invalid value for '-%s' flag \"%s\"", f.Name, f.Value)
+			} else if f.Name == "endAt" && !bound.IsZero() && bound.Before(time.Now()) {
+				err = fmt.Errorf("invalid value for '-endAt' flag \"%s\": it's in the past", f.Value)
+			}
+			if f.Name == "startAt" {
+				settings.startsAt = &bound
+			} else {
+				settings.endsAt = &bound
+			}
+		case "maxOccurrences":
+			maxOccurrences := f.Value.(flag.Getter).Get().(int64)
+			if maxOccurrences < 0 {
+				err = fmt.Errorf("invalid value for '-maxOccurrences' flag \"%d\"", maxOccurrences)
+			}
+			settings.maxOccurrences = &maxOccurrences
+		case "skip", "unskip":
+			dates, parseErr := parseSkipDates(f.Value.String())
+			if parseErr != nil {
+				err = fmt.Errorf("invalid value for '-%s' flag \"%s\": %s", f.Name, f.Value, parseErr)
+			}
+			if f.Name == "skip" {
+				settings.skip = dates
+			} else {
+				settings.unskip = dates
+			}
+		case "calendar":
+			calendar := f.Value.String()
+			settings.calendar = &calendar
+		case "shift":
+			shift := f.Value.(flag.Getter).Get().(bool)
+			settings.shift = &shift
+		case "urgent":
+			urgent := f.Value.(flag.Getter).Get().(bool)
+			settings.urgent = &urgent
+		case "repeatEvery":
+			repeatEvery := f.Value.(flag.Getter).Get().(time.Duration)
+			if repeatEvery < 0 || (repeatEvery > 0 && repeatEvery < time.Second) {
+				err = fmt.Errorf("invalid value for '-repeatEvery' flag \"%s\"", repeatEvery)
+			}
+			settings.repeatEvery = &repeatEvery
+		case "maxRepeats":
+			maxRepeats := f.Value.(flag.Getter).Get().(int64)
+			if maxRepeats < 0 {
+				err = fmt.Errorf("invalid value for '-maxRepeats' flag \"%d\"", maxRepeats)
+			}
+			settings.maxRepeats = &maxRepeats
+		case "template":
+			template := f.Value.String()
+			settings.template = &template
+		case "channel":
+			channel := f.Value.String()
+			settings.channel = &channel
+		case "escalateTo":
+			escalateTo := f.Value.String()
+			if escalateTo != "" {
+				if checkErr := checkSink(escalateTo); checkErr != nil {
+					err = fmt.Errorf("invalid value for '-escalateTo' flag: %s", checkErr)
+				}
+			}
+			settings.escalateTo = &escalateTo
+		}
+	})
+	return settings, err
+}
+
+func (s notificationSettings) empty() bool {
+	return s.catchUp == nil && s.maxLateness == nil && !s.bounded() &&
+		s.skip == nil && s.unskip == nil && s.calendar == nil && s.shift == nil && s.urgent == nil &&
+		s.repeatEvery == nil && s.maxRepeats == nil && s.escalateTo == nil &&
+		s.channel == nil && s.template == nil
+}
+
+// bounded tells whether any of the bounds was given.
+func (s notificationSettings) bounded() bool {
+	return s.startsAt != nil || s.endsAt != nil || s.maxOccurrences != nil
+}
+
+// applySettings stores the given settings of the notification. Its settings
+// row is created along with the notification by a trigger. Changing the bounds
+// of a finished notification makes it run again until notify finds it done.
+func applySettings(ctx context.Context, queries *sqlc.Queries, notId int64, settings notificationSettings) error {
+	if settings.catchUp != nil {
+		if err := queries.UpdateNotificationCatchUp(ctx, sqlc.UpdateNotificationCatchUpParams{
+			CatchUp: *settings.catchUp,
+			NotificationID: notId,
+		}); err != nil { return err }
+	}
+	if settings.maxLateness != nil {
+		maxLateness := sql.NullInt64{}
+		if *settings.maxLateness > 0 {
+			maxLateness = sql.NullInt64{Int64: int64(settings.maxLateness.Round(time.Second) / time.Second), Valid: true}
+		}
+		if err := queries.UpdateNotificationMaxLateness(ctx, sqlc.UpdateNotificationMaxLatenessParams{
+			MaxLateness: maxLateness,
+			NotificationID: notId,
+		}); err != nil { return err }
+	}
+	if settings.startsAt != nil {
+		if err := queries.UpdateNotificationStartsAt(ctx, sqlc.UpdateNotificationStartsAtParams{
+			StartsAt: sql.NullTime{Time: settings.startsAt.UTC(), Valid: !settings.startsAt.IsZero()},
+			NotificationID: notId,
+		}); err != nil { return err }
+	}
+	if settings.endsAt != nil {
+		if err := queries.UpdateNotificationEndsAt(ctx, sqlc.UpdateNotificationEndsAtParams{
+			EndsAt: sql.NullTime{Time: settings.endsAt.UTC(), Valid: !settings.endsAt.IsZero()},
+			NotificationID: notId,
+		}); err != nil { return err }
+	}
+	if settings.maxOccurrences != nil {
+		if err := queries.UpdateNotificationMaxOccurrences(ctx, sqlc.UpdateNotificationMaxOccurrencesParams{
+			MaxOccurrences: sql.NullInt64{Int64: *settings.maxOccurrences, Valid: *settings.maxOccurrences > 0},
+			NotificationID: notId,
+		}); err != nil { return err }
+	}
+
+	for _, d := range settings.skip {
+		if err := queries.CreateNotificationSkipDate(ctx, sqlc.CreateNotificationSkipDateParams{
+			NotificationID: notId,
+			Date: d,
+		}); err != nil { return err }
+	}
+	for _, d := range settings.unskip {
+		deleted, err := queries.DeleteNotificationSkipDate(ctx, sqlc.DeleteNotificationSkipDateParams{
+			NotificationID: notId,
+			Date: d,
+		})
+		if err != nil { return err }
+		if deleted != 1 { return fmt.Errorf("notification doesn't skip %s", localizeDate(d)) }
+	}
+	if settings.calendar != nil {
+		if *settings.calendar != "" {
+			exists, err := queries.HolidayCalendarExists(ctx, *settings.calendar)
+			if err != nil { return err }
+			if exists != 1 { return fmt.Errorf("holiday calendar \"%s\" does not exist", *settings.calendar) }
+		}
+		if err := queries.UpdateNotificationCalendar(ctx, sqlc.UpdateNotificationCalendarParams{
+			Calendar: sql.NullString{String: *settings.calendar, Valid: *settings.calendar != ""},
+			NotificationID: notId,
+		}); err != nil { return err }
+	}
+	if settings.shift != nil {
+		if err := queries.UpdateNotificationShiftSkipped(ctx, sqlc.UpdateNotificationShiftSkippedParams{
+			ShiftSkipped: *settings.shift,
+			NotificationID: notId,
+		}); err != nil { return err }
+	}
+	if settings.urgent != nil {
+		if err := queries.UpdateNotificationUrgent(ctx, sqlc.UpdateNotificationUrgentParams{
+			Urgent: *settings.urgent,
+			NotificationID: notId,
+		}); err != nil { return err }
+	}
+	if settings.repeatEvery != nil {
+		repeatEvery := sql.NullInt64{}
+		if *settings.repeatEvery > 0 {
+			repeatEvery = sql.NullInt64{Int64: int64(settings.repeatEvery.Round(time.Second) / time.Second), Valid: true}
+		}
+		if err := queries.UpdateNotificationRepeatEvery(ctx, sqlc.UpdateNotificationRepeatEveryParams{
+			RepeatEvery: repeatEvery,
+			NotificationID: notId,
+		}); err != nil { return err }
+	}
+	if settings.maxRepeats != nil {
+		if err := queries.UpdateNotificationMaxRepeats(ctx, sqlc.UpdateNotificationMaxRepeatsParams{
+			MaxRepeats: sql.NullInt64{Int64: *settings.maxRepeats, Valid: *settings.maxRepeats > 0},
+			NotificationID: notId,
+		}); err != nil { return err }
+	}
+	if settings.escalateTo != nil {
+		if err := queries.UpdateNotificationEscalateTo(ctx, sqlc.UpdateNotificationEscalateToParams{
+			EscalateTo: sql.NullString{String: *settings.escalateTo, Valid: *settings.escalateTo != ""},
+			NotificationID: notId,
+		}); err != nil { return err }
+	}
+	if settings.channel != nil {
+		if *settings.channel != "" {
+			c, err := config.Load()
+			if err != nil { return err }
+			if _, ok := c.Channels[*settings.channel]; !ok {
+				return fmt.Errorf("channel \"%s\" is not in the config file", *settings.channel)
+			}
+		}
+		if err := queries.UpdateNotificationChannel(ctx, sqlc.UpdateNotificationChannelParams{
+			Channel: sql.NullString{String: *settings.channel, Valid: *settings.channel != ""},
+			NotificationID: notId,
+		}); err != nil { return err }
+	}
+	if settings.template != nil {
+		if _, err := parseTemplate("template", *settings.template); err != nil { return err }
+		if err := queries.UpdateNotificationTemplate(ctx, sqlc.UpdateNotificationTemplateParams{
+			Template: sql.NullString{String: *settings.template, Valid: *settings.template != ""},
+			NotificationID: notId,
+		}); err != nil { return err }
+	}
+
+	if !settings.bounded() { return nil }
+
+	stored, err := queries.GetNotificationSettingsByNotificationId(ctx, notId)
+	if err != nil { return err }
+	if stored.StartsAt.Valid && stored.EndsAt.Valid && stored.EndsAt.Time.Before(stored.StartsAt.Time) {
+		return errors.New("-endAt must not be before -startAt")
+	}
+	return queries.UnfinishNotification(ctx, notId)
+}
+
+// catchUp applies the catch-up policy to the due occurrences. The ones at or
+// before since were missed, the later ones are on time and always kept.
+func catchUp(policy string, occurrences []time.Time, since time.Time) []time.Time {
+	i := slices.IndexFunc(occurrences, func(at time.Time) bool { return at.After(since) })
+	if i == -1 { i = len(occurrences) }
+	missed, onTime := occurrences[:i], occurrences[i:]
+
+	switch policy {
+	case "skip":
+		return onTime
+	case "latest":
+		if len(missed) > 1 { missed = missed[len(missed)-1:] }
+	}
+	return append(slices.Clone(missed), onTime...)
+}
+
+// dueSince returns where to start looking for due occurrences of the
+// notification: right after the last one handled, or when it was created.
+func dueSince(ctx context.Context, queries *sqlc.Queries, notification sqlc.Notification, settings sqlc.NotificationSetting, now time.Time) (time.Time, error) {
+	from := notification.CreatedAt
+
+	last, err := queries.GetLastOccurrenceAt(ctx, notification.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) { return time.Time{}, err }
+	if err == nil && last.After(from) { from = last }
+
+	if settings.MaxLateness.Valid {
+		oldest := now.Add(-time.Duration(settings.MaxLateness.Int64) * time.Second)
+		if oldest.After(from) { from = oldest }
+	}
+	return from, nil
+}
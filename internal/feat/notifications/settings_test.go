@@ -0,0 +1,85 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+)
+
+func TestCatchUp(t *testing.T) {
+	hour := func(h int) time.Time { return agendaStart.Add(time.Duration(h) * time.Hour) }
+	occurrences := []time.Time{hour(1), hour(2), hour(3), hour(4)}
+
+	tests := []struct {
+		policy string
+		since  time.Time
+		want   []time.Time
+	}{
+		// 01:00 and 02:00 were missed, the later ones are on time
+		{"all", hour(2), []time.Time{hour(1), hour(2), hour(3), hour(4)}},
+		{"latest", hour(2), []time.Time{hour(2), hour(3), hour(4)}},
+		{"skip", hour(2), []time.Time{hour(3), hour(4)}},
+
+		// nothing was missed
+		{"all", hour(0), occurrences},
+		{"latest", hour(0), occurrences},
+		{"skip", hour(0), occurrences},
+
+		// everything was missed
+		{"all", hour(4), occurrences},
+		{"latest", hour(4), []time.Time{hour(4)}},
+		{"skip", hour(4), []time.Time{}},
+	}
+
+	for _, tt := range tests {
+		got := catchUp(tt.policy, occurrences, tt.since)
+		if len(got) != len(tt.want) {
+			t.Errorf("catchUp(%s, since %s) = %s, want %s", tt.policy, tt.since, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("catchUp(%s, since %s) = %s, want %s", tt.policy, tt.since, got, tt.want)
+				break
+			}
+		}
+	}
+	if got := catchUp("latest", nil, agendaStart); len(got) != 0 { t.Errorf("catchUp(latest) of nothing = %s", got) }
+}
+
+func TestDueSince(t *testing.T) {
+	db, queries := testDb(t)
+	ctx := context.Background()
+	n := testCron(t, db, queries, "drink water", "0 * * * *")
+	now := agendaStart.Add(3 * time.Hour)
+	lateness := func(d time.Duration) sqlc.NotificationSetting {
+		return sqlc.NotificationSetting{MaxLateness: sql.NullInt64{Int64: int64(d.Seconds()), Valid: true}}
+	}
+
+	tests := []struct {
+		name     string
+		deliver  []time.Time
+		settings sqlc.NotificationSetting
+		want     time.Time
+	}{
+		{"when created", nil, sqlc.NotificationSetting{}, n.notification.CreatedAt},
+		{"max lateness", nil, lateness(30 * time.Minute), now.Add(-30 * time.Minute)},
+		{"max lateness before it was created", nil, lateness(24 * time.Hour), n.notification.CreatedAt},
+		{"last occurrence", []time.Time{agendaStart, agendaStart.Add(time.Hour)}, sqlc.NotificationSetting{}, agendaStart.Add(time.Hour)},
+		{"max lateness after the last occurrence", nil, lateness(30 * time.Minute), now.Add(-30 * time.Minute)},
+		{"last occurrence after max lateness", nil, lateness(24 * time.Hour), agendaStart.Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		for _, at := range tt.deliver {
+			if err := deliver(ctx, queries, okSink{}, n.notification, n.message, at, false); err != nil { t.Fatal(err) }
+		}
+
+		got, err := dueSince(ctx, queries, n.notification, tt.settings, now)
+		if err != nil { t.Fatal(err) }
+		if !got.Equal(tt.want) { t.Errorf("%s: dueSince = %s, want %s", tt.name, got, tt.want) }
+	}
+}
@@ -0,0 +1,232 @@
+package notifications
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/config"
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+// notice is a due occurrence of a notification.
+type notice struct {
+	notification sqlc.Notification
+	message      sqlc.Message
+	at           time.Time
+	repeat       int64 // 0 the first time the occurrence is sent
+	deliveryID   int64 // 0 when the send isn't tracked
+	features     map[string]map[string]any
+	template     *template.Template // nil renders the default line
+}
+
+// line renders the notice with its template, or else the way notify()
+// always printed it, e.g. `[S] "text" (-5s)`. Repeats get one '!' more each
+// time, e.g. `!! [S] "text" (-20m0s, repeat 2)`.
+func (n notice) line(now time.Time) string {
+	if line, ok := n.render(now); ok { return line }
+	if n.repeat > 0 {
+		return fmt.Sprintf("%s [%s] \"%s\" (%s, repeat %d)", strings.Repeat("!", int(n.repeat)),
+			strings.ToUpper(string(n.notification.Type[0])), n.message.Text, n.at.Sub(now).Round(time.Second), n.repeat)
+	}
+	return fmt.Sprintf("[%s] \"%s\" (%s)",
+		strings.ToUpper(string(n.notification.Type[0])), n.message.Text, n.at.Sub(now).Round(time.Second))
+}
+
+// payload is how sinks that take JSON receive a notice.
+type payload struct {
+	MessageID      int64     `json:"message_id"`
+	Text           string    `json:"text"`
+	Line           string    `json:"line"`
+	NotificationID int64     `json:"notification_id"`
+	Type           string    `json:"type"`
+	OccurrenceAt   time.Time `json:"occurrence_at"`
+	SentAt         time.Time `json:"sent_at"`
+	Repeat         int64     `json:"repeat,omitempty"`
+}
+
+func (n notice) payload(now time.Time) payload {
+	return payload{
+		MessageID: n.message.ID,
+		Text: n.message.Text,
+		Line: n.line(now),
+		NotificationID: n.notification.ID,
+		Type: n.notification.Type,
+		OccurrenceAt: n.at.In(time.Local),
+		SentAt: now,
+		Repeat: n.repeat,
+	}
+}
+
+// summaryPayload is how sinks that take JSON receive the summary of the
+// notices held back during quiet hours.
+type summaryPayload struct {
+	Text  string    `json:"text"`
+	Held  int       `json:"held"`
+	Since time.Time `json:"since"`
+}
+
+func heldSummary(held []notice) summaryPayload {
+	text := fmt.Sprintf("%d notification", len(held))
+	if len(held) != 1 { text += "s" }
+	text += fmt.Sprintf(" held back during quiet hours, since %s:", utils.LocalizeDateTime(held[0].at.In(time.Local)))
+	return summaryPayload{Text: text, Held: len(held), Since: held[0].at.In(time.Local)}
+}
+
+// sink is where due notifications are sent.
+type sink interface {
+	send(n notice) error
+	// summary announces the notices held back during quiet hours, right
+	// before they're sent.
+	summary(held []notice) error
+	close() error
+}
+
+// retrier is implemented by sinks that space out the retries of a failed
+// send. The others are retried on every run.
+type retrier interface {
+	// retryAt returns when to retry a send that failed attempts times, the
+	// zero time meaning on the next run, or false to give up on it.
+	retryAt(attempts int64, now time.Time) (time.Time, bool)
+}
+
+// maxBackoff caps the wait between two retries.
+const maxBackoff = 6 * time.Hour
+
+// backoff waits the backoff of the retries after the first failed attempt,
+// twice as long after every other one, and gives up after the max attempts.
+func backoff(retries config.Retries, attempts int64, now time.Time) (time.Time, bool) {
+	if attempts >= int64(retries.MaxAttemptsOrDefault()) { return time.Time{}, false }
+
+	wait := retries.BackoffOrDefault()
+	for i := int64(1); i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	return now.Add(min(wait, maxBackoff)), true
+}
+
+type writerSink struct {
+	w         io.Writer
+	c         io.Closer
+	timestamp bool
+}
+
+func (s writerSink) send(n notice) error {
+	now := time.Now()
+	line := n.line(now)
+	if s.timestamp {
+		line = now.Format(time.RFC3339) + " " + line
+	}
+	_, err := fmt.Fprintln(s.w, line)
+	return err
+}
+
+func (s writerSink) summary(held []notice) error {
+	line := heldSummary(held).Text
+	if s.timestamp {
+		line = time.Now().Format(time.RFC3339) + " " + line
+	}
+	_, err := fmt.Fprintln(s.w, line)
+	return err
+}
+
+func (s writerSink) close() error {
+	if s.c == nil { return nil }
+	return s.c.Close()
+}
+
+// jsonSink appends one JSON object per line.
+type jsonSink struct {
+	f *os.File
+}
+
+func (s jsonSink) write(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil { return err }
+	_, err = s.f.Write(append(data, '\n'))
+	return err
+}
+
+func (s jsonSink) send(n notice) error {
+	return s.write(n.payload(time.Now()))
+}
+
+func (s jsonSink) summary(held []notice) error {
+	return s.write(heldSummary(held))
+}
+
+func (s jsonSink) close() error {
+	return s.f.Close()
+}
+
+// sinkUsage lists the sink specs, for flag usages and errors.
+const sinkUsage = "\"stdout\", \"file:PATH\", \"jsonl:PATH\", \"exec:COMMAND\", \"syslog[:TAG]\", \"webhook:NAME|URL\" or \"email:NAME\""
+
+// parseSink splits a sink spec into its kind and argument, failing when it
+// isn't valid. Nothing is opened.
+func parseSink(spec string) (string, string, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "stdout":
+		if arg == "" { return kind, arg, nil }
+	case "file", "jsonl":
+		if arg == "" { return "", "", fmt.Errorf("%s sink needs a path, e.g. \"%s:/tmp/gmess.log\"", kind, kind) }
+		return kind, arg, nil
+	case "exec":
+		if strings.TrimSpace(arg) == "" { return "", "", errors.New("exec sink needs a command, e.g. \"exec:notify-send gmess \\\"$GMESS_TEXT\\\"\"") }
+		return kind, arg, nil
+	case "syslog":
+		if arg == "" { arg = "gmess" }
+		return kind, arg, nil
+	case "webhook":
+		if arg == "" { return "", "", errors.New("webhook sink needs the name of a webhook in the config file or a URL") }
+		return kind, arg, nil
+	case "email":
+		if arg == "" { return "", "", errors.New("email sink needs the name of an email in the config file") }
+		return kind, arg, nil
+	}
+	return "", "", fmt.Errorf("invalid sink \"%s\", use %s", spec, sinkUsage)
+}
+
+// checkSink fails when spec isn't a valid sink spec, without opening it.
+func checkSink(spec string) error {
+	_, _, err := parseSink(spec)
+	return err
+}
+
+// openSink opens a sink spec:
+//   - "stdout" prints one line per notification,
+//   - "file:PATH" appends one timestamped line per notification,
+//   - "jsonl:PATH" appends one JSON object per notification,
+//   - "exec:COMMAND" runs the command for each notification,
+//   - "syslog[:TAG]" logs to the local syslog, tagged "gmess" by default,
+//   - "webhook:NAME" posts to a webhook of the config file, "webhook:URL"
+//     to the URL,
+//   - "email:NAME" sends an email as set in the config file.
+func openSink(spec string) (sink, error) {
+	kind, arg, err := parseSink(spec)
+	if err != nil { return nil, err }
+
+	switch kind {
+	case "file", "jsonl":
+		f, err := os.OpenFile(arg, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil { return nil, err }
+		if kind == "jsonl" { return jsonSink{f: f}, nil }
+		return writerSink{w: f, c: f, timestamp: true}, nil
+	case "exec":
+		return execSink{command: arg}, nil
+	case "syslog":
+		return openSyslogSink(arg)
+	case "webhook":
+		return openWebhookSink(arg)
+	case "email":
+		return openEmailSink(arg)
+	}
+	return writerSink{w: os.Stdout}, nil
+}
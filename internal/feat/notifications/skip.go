@@ -0,0 +1,285 @@
+package notifications
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+// skipDateLayout is how skipped dates and holidays are stored: local calendar
+// days, so they don't depend on the time of the occurrences.
+const skipDateLayout = "2006-01-02"
+
+// maxShiftDays is how far an occurrence on a skipped date may move looking
+// for a business day. Occurrences that can't find one are dropped.
+const maxShiftDays = 14
+
+// parseSkipDates parses a ';' separated list of "DD/MM/YY" dates.
+func parseSkipDates(skip string) ([]string, error) {
+	skipDLayout := "02/01/06" // "DD/MM/YY"
+
+	dates := []string{}
+	for _, d := range strings.Split(skip, ";") {
+		d = strings.TrimSpace(d)
+		if d == "" { continue }
+
+		date, err := time.Parse(skipDLayout, d)
+		if err != nil { return nil, err }
+		if !slices.Contains(dates, date.Format(skipDateLayout)) {
+			dates = append(dates, date.Format(skipDateLayout))
+		}
+	}
+	if len(dates) == 0 { return nil, errors.New("no dates given") }
+
+	return dates, nil
+}
+
+// localizeDate renders a stored skip date with utils.LocalizeDate.
+func localizeDate(date string) string {
+	t, err := time.ParseInLocation(skipDateLayout, date, time.Local)
+	if err != nil { return date }
+	return utils.LocalizeDate(t)
+}
+
+// exceptSchedule drops the occurrences of a schedule that fall on skipped
+// dates, or moves them to the next business day when shift is set.
+type exceptSchedule struct {
+	schedule
+	dates map[string]bool
+	shift bool
+}
+
+func (s exceptSchedule) skipped(t time.Time) bool {
+	return s.dates[t.In(time.Local).Format(skipDateLayout)]
+}
+
+func (s exceptSchedule) businessDay(t time.Time) bool {
+	wd := t.In(time.Local).Weekday()
+	return wd != time.Saturday && wd != time.Sunday && !s.skipped(t)
+}
+
+// nextBusinessDay returns the first business day after at's, keeping its time
+// of day.
+func (s exceptSchedule) nextBusinessDay(at time.Time) (time.Time, bool) {
+	at = at.In(time.Local)
+	for i := 1; i <= maxShiftDays; i++ {
+		day := time.Date(at.Year(), at.Month(), at.Day() + i, at.Hour(), at.Minute(), at.Second(), 0, time.Local)
+		if s.businessDay(day) { return day, true }
+	}
+	return time.Time{}, false
+}
+
+func (s exceptSchedule) between(from time.Time, to time.Time) []time.Time {
+	occurrences := []time.Time{}
+	if !s.shift {
+		for _, at := range s.schedule.between(from, to) {
+			if !s.skipped(at) { occurrences = append(occurrences, at) }
+		}
+		return occurrences
+	}
+
+	// Occurrences from before from may have been shifted into (from, to].
+	for _, at := range s.schedule.between(from.AddDate(0, 0, -maxShiftDays), to) {
+		if s.skipped(at) {
+			var ok bool
+			if at, ok = s.nextBusinessDay(at); !ok { continue }
+		}
+		if at.After(from) && !at.After(to) && !slices.ContainsFunc(occurrences, at.Equal) {
+			occurrences = append(occurrences, at)
+		}
+	}
+	slices.SortFunc(occurrences, func(a, b time.Time) int { return a.Compare(b) })
+	return occurrences
+}
+
+// loadSkipDates returns the dates skipped by the notification, its own and
+// the ones of its holiday calendar.
+func loadSkipDates(ctx context.Context, queries *sqlc.Queries, notId int64, settings sqlc.NotificationSetting) (map[string]bool, error) {
+	dates := map[string]bool{}
+
+	skipDates, err := queries.GetNotificationSkipDates(ctx, notId)
+	if err != nil { return nil, err }
+	for _, d := range skipDates {
+		dates[d] = true
+	}
+
+	if settings.Calendar.Valid {
+		holidays, err := queries.GetHolidayCalendarDates(ctx, settings.Calendar.String)
+		if err != nil { return nil, err }
+		for _, h := range holidays {
+			dates[h.Date] = true
+		}
+	}
+	return dates, nil
+}
+
+// parseCalendarFile reads a holiday calendar: one date per line, DD/MM/YY,
+// DD/MM/YYYY or YYYY-MM-DD, optionally followed by a description. Blank
+// lines and lines starting with '#' are ignored.
+func parseCalendarFile(path string) ([]sqlc.HolidayCalendarDate, error) {
+	file, err := os.Open(path)
+	if err != nil { return nil, err }
+	defer file.Close()
+
+	holidays := []sqlc.HolidayCalendarDate{}
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") { continue }
+
+		d, description, _ := strings.Cut(line, " ")
+
+		var date time.Time
+		for _, layout := range []string{"02/01/06", "02/01/2006", skipDateLayout} {
+			if date, err = time.Parse(layout, d); err == nil { break }
+		}
+		if err != nil { return nil, fmt.Errorf("line %d: invalid date \"%s\"", n, d) }
+
+		holidays = append(holidays, sqlc.HolidayCalendarDate{
+			Date: date.Format(skipDateLayout),
+			Description: strings.TrimSpace(description),
+		})
+	}
+	if err := scanner.Err(); err != nil { return nil, err }
+
+	return holidays, nil
+}
+
+// importCalendar creates the holiday calendar from a file, replacing its
+// dates if it already exists.
+func importCalendar(name string, path string) (int, error) {
+	holidays, err := parseCalendarFile(path)
+	if err != nil { return 0, err }
+
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return 0, err }
+
+	queries := sqlc.New(db)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil { return 0, err }
+	qtx := queries.WithTx(tx)
+
+	if err = qtx.CreateHolidayCalendar(ctx, name); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err = qtx.DeleteHolidayCalendarDates(ctx, name); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	for _, h := range holidays {
+		if err = qtx.CreateHolidayCalendarDate(ctx, sqlc.CreateHolidayCalendarDateParams{
+			Calendar: name,
+			Date: h.Date,
+			Description: h.Description,
+		}); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	return len(holidays), tx.Commit()
+}
+
+// removeCalendar deletes the holiday calendar. Notifications using it are
+// left without one.
+func removeCalendar(name string) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+
+	queries := sqlc.New(db)
+
+	deleted, err := queries.DeleteHolidayCalendar(ctx, name)
+	if err != nil { return err }
+	if deleted != 1 { return fmt.Errorf("holiday calendar \"%s\" does not exist", name) }
+	return nil
+}
+
+func showCalendars(name string) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+
+	queries := sqlc.New(db)
+
+	if name == "" {
+		calendars, err := queries.GetHolidayCalendars(ctx)
+		if err != nil { return err }
+
+		if len(calendars) == 0 {
+			fmt.Println("You have no holiday calendars")
+			return nil
+		}
+		for _, c := range calendars {
+			fmt.Printf("%s (%d dates)\n", c.Name, c.Dates)
+		}
+		return nil
+	}
+
+	exists, err := queries.HolidayCalendarExists(ctx, name)
+	if err != nil { return err }
+	if exists != 1 { return fmt.Errorf("holiday calendar \"%s\" does not exist", name) }
+
+	holidays, err := queries.GetHolidayCalendarDates(ctx, name)
+	if err != nil { return err }
+	for _, h := range holidays {
+		if h.Description == "" {
+			fmt.Println(localizeDate(h.Date))
+			continue
+		}
+		fmt.Printf("%s: %s\n", localizeDate(h.Date), h.Description)
+	}
+	return nil
+}
+
+func calendarCmd(args []string) {
+	cmd := flag.NewFlagSet("calendar", flag.ExitOnError)
+	nameFlag := cmd.String("name", "", "holiday calendar name")
+	importFlag := cmd.String("import", "", "create the calendar from this file, replacing its dates if it exists\none date per line (DD/MM/YY, DD/MM/YYYY or YYYY-MM-DD), optionally followed by a description")
+	rmFlag := cmd.Bool("rm", false, "remove the calendar")
+
+	if err := cmd.Parse(args); err != nil {
+		fmt.Printf("error parsing cli args: %s\n", err)
+		os.Exit(1)
+	}
+
+	if *importFlag != "" && *rmFlag {
+		fmt.Println("flags -import and -rm can't be used together")
+		os.Exit(1)
+	}
+
+	switch {
+	case *importFlag != "":
+		utils.EnforceRequiredFlags(cmd, []string{"name"})
+		count, err := importCalendar(*nameFlag, *importFlag)
+		if err != nil {
+			fmt.Printf("error importing holiday calendar: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("holiday calendar \"%s\" imported (%d dates)\n", *nameFlag, count)
+	case *rmFlag:
+		utils.EnforceRequiredFlags(cmd, []string{"name"})
+		if err := removeCalendar(*nameFlag); err != nil {
+			fmt.Printf("error removing holiday calendar: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("holiday calendar \"%s\" removed\n", *nameFlag)
+	default:
+		if err := showCalendars(*nameFlag); err != nil {
+			fmt.Printf("error showing holiday calendars: %s\n", err)
+			os.Exit(1)
+		}
+	}
+}
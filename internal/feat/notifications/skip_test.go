@@ -0,0 +1,99 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExceptBetween(t *testing.T) {
+	// 07/01/2030 is a monday
+	date := func(day int) time.Time {
+		return time.Date(2030, 1, day, 9, 0, 0, 0, time.Local)
+	}
+	daily := intervalSchedule{every: 1, unit: "day", anchor: date(7)}
+	fridays := intervalSchedule{every: 1, unit: "week", anchor: date(11)}
+	skip := func(days ...int) map[string]bool {
+		dates := map[string]bool{}
+		for _, day := range days {
+			dates[date(day).Format(skipDateLayout)] = true
+		}
+		return dates
+	}
+
+	tests := []struct {
+		name     string
+		schedule exceptSchedule
+		from     time.Time
+		to       time.Time
+		want     []time.Time
+	}{
+		{
+			"skip", exceptSchedule{schedule: daily, dates: skip(8, 9)},
+			date(7).Add(-time.Hour), date(10),
+			[]time.Time{date(7), date(10)},
+		},
+		{
+			"skip nothing", exceptSchedule{schedule: fridays, dates: skip(8)},
+			date(7), date(25),
+			[]time.Time{date(11), date(18), date(25)},
+		},
+		{
+			"shift", exceptSchedule{schedule: fridays, dates: skip(18), shift: true},
+			date(7), date(25),
+			[]time.Time{date(11), date(21), date(25)},
+		},
+		// the occurrence of the 8th moves to the 9th, which already has one
+		{
+			"shift onto an occurrence", exceptSchedule{schedule: daily, dates: skip(8), shift: true},
+			date(7).Add(-time.Hour), date(10),
+			[]time.Time{date(7), date(9), date(10)},
+		},
+		{
+			"shift onto a skipped date", exceptSchedule{schedule: fridays, dates: skip(18, 21, 22), shift: true},
+			date(11), date(25),
+			[]time.Time{date(23), date(25)},
+		},
+		{
+			"shift out of the range", exceptSchedule{schedule: fridays, dates: skip(18), shift: true},
+			date(17), date(20),
+			[]time.Time{},
+		},
+		{
+			"shift into the range", exceptSchedule{schedule: fridays, dates: skip(18), shift: true},
+			date(20), date(21),
+			[]time.Time{date(21)},
+		},
+		// from is excluded
+		{
+			"shift to from", exceptSchedule{schedule: fridays, dates: skip(18), shift: true},
+			date(21), date(24),
+			[]time.Time{},
+		},
+		// the 18th finds no business day within maxShiftDays, up to 01/02
+		{
+			"no business day", exceptSchedule{schedule: fridays, dates: skip(18, 21, 22, 23, 24, 25, 28, 29, 30, 31, 32), shift: true},
+			date(11), date(32),
+			[]time.Time{},
+		},
+		// the 18th and the 25th move to 01/02, maxShiftDays after the 18th
+		{
+			"a business day maxShiftDays later", exceptSchedule{schedule: fridays, dates: skip(18, 21, 22, 23, 24, 25, 28, 29, 30, 31), shift: true},
+			date(11), date(32),
+			[]time.Time{date(32)},
+		},
+	}
+
+	for _, tt := range tests {
+		got := tt.schedule.between(tt.from, tt.to)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: between(%s, %s) = %s, want %s", tt.name, tt.from, tt.to, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("%s: between(%s, %s) = %s, want %s", tt.name, tt.from, tt.to, got, tt.want)
+				break
+			}
+		}
+	}
+}
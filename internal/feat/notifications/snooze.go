@@ -0,0 +1,187 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+// currentDelivery is the last occurrence of the notification that was sent
+// (or failed to) and wasn't acknowledged yet.
+func currentDelivery(ctx context.Context, queries *sqlc.Queries, notId int64) (sqlc.Delivery, error) {
+	exists, err := queries.NotificationExists(ctx, notId)
+	if err != nil { return sqlc.Delivery{}, err }
+	if exists != 1 { return sqlc.Delivery{}, errors.New("notification does not exist") }
+
+	delivery, err := queries.GetCurrentDelivery(ctx, notId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return delivery, errors.New("notification has no unacknowledged occurrence")
+	}
+	return delivery, err
+}
+
+// snooze sends the current occurrence of the notification again at the given
+// time. The notification itself is left untouched.
+func snooze(notId int64, until time.Time) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+
+	queries := sqlc.New(db)
+
+	delivery, err := currentDelivery(ctx, queries, notId)
+	if err != nil { return err }
+
+	return queries.SnoozeDelivery(ctx, sqlc.SnoozeDeliveryParams{
+		SnoozedUntil: sql.NullTime{Time: until.UTC(), Valid: true},
+		ID: delivery.ID,
+	})
+}
+
+func ack(notId int64) error {
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+
+	queries := sqlc.New(db)
+
+	delivery, err := currentDelivery(ctx, queries, notId)
+	if err != nil { return err }
+
+	return queries.AckDelivery(ctx, sqlc.AckDeliveryParams{
+		AckedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ID: delivery.ID,
+	})
+}
+
+// notifySnoozed sends again every snoozed occurrence whose time has come,
+// holding back the ones that aren't urgent when hold is set. A failed send
+// is retried like any other, as the sink says.
+func notifySnoozed(ctx context.Context, queries *sqlc.Queries, s sink, now time.Time, hold bool) error {
+	deliveries, err := queries.GetDueSnoozedDeliveries(ctx, sql.NullTime{Time: now.UTC(), Valid: true})
+	if err != nil { return err }
+
+	for _, delivery := range deliveries {
+		// The snoozed send is a new one, its attempts are counted from 1.
+		delivery, err := queries.ClaimSnoozedDelivery(ctx, sqlc.ClaimSnoozedDeliveryParams{
+			ClaimedAt: time.Now().UTC(),
+			ID: delivery.ID,
+			SnoozedUntil: sql.NullTime{Time: now.UTC(), Valid: true},
+		})
+		if errors.Is(err, sql.ErrNoRows) { continue }
+		if err != nil { return err }
+
+		notification, err := queries.GetNotificationById(ctx, delivery.NotificationID)
+		if err != nil { return err }
+		message, err := queries.GetMessageById(ctx, notification.MessageID)
+		if err != nil { return err }
+
+		if hold {
+			settings, err := queries.GetNotificationSettingsByNotificationId(ctx, notification.ID)
+			if err != nil { return err }
+			if !settings.Urgent {
+				if err := queries.SetDeliveryHeld(ctx, delivery.ID); err != nil { return err }
+				continue
+			}
+		}
+
+		n, err := newNotice(ctx, queries, notification, message, delivery.OccurrenceAt, 0)
+		if err != nil { return err }
+		n.deliveryID = delivery.ID
+		if err := s.send(n); err != nil {
+			fmt.Fprintf(os.Stderr, "error delivering notification (%d): %s\n", notification.ID, err)
+			if err := failDelivery(ctx, queries, s, delivery, err); err != nil { return err }
+			continue
+		}
+
+		if err := queries.SetDeliveryDelivered(ctx, sqlc.SetDeliveryDeliveredParams{
+			DeliveredAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+			ID: delivery.ID,
+		}); err != nil { return err }
+
+		if err := scheduleRepeat(ctx, queries, delivery, delivery.Repeats); err != nil { return err }
+	}
+	return nil
+}
+
+// parseUntil accepts "DD/MM/YY HH-MM-SS" or "HH-MM-SS", the latter meaning
+// the next time the clock reads that time.
+func parseUntil(until string, now time.Time) (time.Time, error) {
+	triggerAtDLayout := "02/01/06 15-04-05" // "DD/MM/YY HH-MM-SS"
+	triggerAtTLayout := "15-04-05" // "HH-MM-SS"
+
+	if t, err := time.Parse(triggerAtTLayout, until); err == nil {
+		now = now.In(time.Local)
+		at := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local)
+		if !at.After(now) { at = at.AddDate(0, 0, 1) }
+		return at, nil
+	}
+
+	t, err := time.Parse(triggerAtDLayout, until)
+	if err != nil { return time.Time{}, err }
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local), nil
+}
+
+func snoozeCmd(args []string) {
+	cmd := flag.NewFlagSet("snooze", flag.ExitOnError)
+	notIdFlag := cmd.Int64("notId", -1, "notification id")
+	forFlag := cmd.Duration("for", 0, "snooze for a duration (e.g. 15m, 2h)")
+	untilFlag := cmd.String("until", "", "snooze until\nlayout: DD/MM/YY HH-MM-SS or HH-MM-SS")
+
+	if err := cmd.Parse(args); err != nil {
+		fmt.Printf("error parsing cli args: %s\n", err)
+		os.Exit(1)
+	}
+
+	utils.EnforceRequiredFlags(cmd, []string{"notId"})
+	if (*forFlag > 0) == (*untilFlag != "") {
+		fmt.Println("pass either -for or -until")
+		cmd.Usage()
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	until := now.Add(*forFlag)
+	if *untilFlag != "" {
+		var err error
+		until, err = parseUntil(*untilFlag, now)
+		if err != nil {
+			fmt.Printf("error parsing until date: %s\n", err)
+			os.Exit(1)
+		}
+		if !until.After(now) {
+			fmt.Println("-until must be in the future")
+			os.Exit(1)
+		}
+	}
+
+	if err := snooze(*notIdFlag, until); err != nil {
+		fmt.Printf("error snoozing notification: %s\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("notification (%d) snoozed until %s\n", *notIdFlag, utils.LocalizeDateTime(until))
+}
+
+func ackCmd(args []string) {
+	cmd := flag.NewFlagSet("ack", flag.ExitOnError)
+	notIdFlag := cmd.Int64("notId", -1, "notification id")
+
+	if err := cmd.Parse(args); err != nil {
+		fmt.Printf("error parsing cli args: %s\n", err)
+		os.Exit(1)
+	}
+
+	utils.EnforceRequiredFlags(cmd, []string{"notId"})
+	if err := ack(*notIdFlag); err != nil {
+		fmt.Printf("error acknowledging notification: %s\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("notification (%d) acknowledged\n", *notIdFlag)
+}
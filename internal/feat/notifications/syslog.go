@@ -0,0 +1,34 @@
+//go:build !windows && !plan9
+
+package notifications
+
+import (
+	"log/syslog"
+	"time"
+)
+
+// syslogSink logs notifications to the local syslog. Repeats are logged as
+// warnings.
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+func openSyslogSink(tag string) (sink, error) {
+	w, err := syslog.New(syslog.LOG_NOTICE|syslog.LOG_USER, tag)
+	if err != nil { return nil, err }
+	return syslogSink{w: w}, nil
+}
+
+func (s syslogSink) send(n notice) error {
+	line := n.line(time.Now())
+	if n.repeat > 0 { return s.w.Warning(line) }
+	return s.w.Notice(line)
+}
+
+func (s syslogSink) summary(held []notice) error {
+	return s.w.Notice(heldSummary(held).Text)
+}
+
+func (s syslogSink) close() error {
+	return s.w.Close()
+}
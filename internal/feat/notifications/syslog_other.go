@@ -0,0 +1,9 @@
+//go:build windows || plan9
+
+package notifications
+
+import "errors"
+
+func openSyslogSink(tag string) (sink, error) {
+	return nil, errors.New("syslog sink is not supported on this system")
+}
@@ -0,0 +1,132 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/feat"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+// templateFuncs are the functions templates can use on top of the builtin
+// ones.
+var templateFuncs = template.FuncMap{
+	"duration": shortDuration,
+	"datetime": func(t time.Time) string { return utils.LocalizeDateTime(t.In(time.Local)) },
+}
+
+// parseTemplate parses the template of a notice line, named after where it's
+// set, e.g. `{{.Text}} ({{duration .Late}} late)`.
+func parseTemplate(name string, text string) (*template.Template, error) {
+	t, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil { return nil, fmt.Errorf("invalid template: %w", err) }
+	return t, nil
+}
+
+// shortDuration rounds d to the second, or to the minute past an hour, and
+// leaves out its zero minutes and seconds, e.g. "45s", "2h" or "1h30m".
+func shortDuration(d time.Duration) string {
+	round := time.Second
+	if d >= time.Hour || d <= -time.Hour { round = time.Minute }
+	s := d.Round(round).String()
+	if strings.HasSuffix(s, "m0s") { s = strings.TrimSuffix(s, "0s") }
+	if strings.HasSuffix(s, "h0m") { s = strings.TrimSuffix(s, "0m") }
+	return s
+}
+
+// templateData is what templates render.
+type templateData struct {
+	MessageID      int64
+	Text           string
+	NotificationID int64
+	Type           string
+	ScheduledAt    time.Time
+	SentAt         time.Time
+	// Late is how long after ScheduledAt the notice is sent, 0 when it's
+	// sent within a minute.
+	Late   time.Duration
+	Repeat int64
+	// Features holds the fields of the message's other features, by name,
+	// e.g. .Features.todos.Status.
+	Features map[string]map[string]any
+}
+
+func (n notice) templateData(now time.Time) templateData {
+	late := now.Sub(n.at).Round(time.Second)
+	if late < time.Minute { late = 0 }
+
+	return templateData{
+		MessageID: n.message.ID,
+		Text: n.message.Text,
+		NotificationID: n.notification.ID,
+		Type: n.notification.Type,
+		ScheduledAt: n.at.In(time.Local),
+		SentAt: now,
+		Late: late,
+		Repeat: n.repeat,
+		Features: n.features,
+	}
+}
+
+// render renders the notice with its template. When it fails, the error is
+// printed and the default line is used instead.
+func (n notice) render(now time.Time) (string, bool) {
+	if n.template == nil { return "", false }
+
+	var sb strings.Builder
+	if err := n.template.Execute(&sb, n.templateData(now)); err != nil {
+		fmt.Fprintf(os.Stderr, "error rendering notification (%d): %s\n", n.notification.ID, err)
+		return "", false
+	}
+	return sb.String(), true
+}
+
+// newNotice gathers what the occurrence of the notification is rendered with:
+// the fields of the message's features and the notification's own template.
+func newNotice(ctx context.Context, queries *sqlc.Queries, notification sqlc.Notification, message sqlc.Message, at time.Time, repeat int64) (notice, error) {
+	n := notice{notification: notification, message: message, at: at, repeat: repeat}
+
+	features, err := feat.MessageFields(ctx, queries, message.ID)
+	if err != nil { return notice{}, err }
+	n.features = features
+
+	settings, err := queries.GetNotificationSettingsByNotificationId(ctx, notification.ID)
+	if err != nil { return notice{}, err }
+	if settings.Template.Valid {
+		t, err := parseTemplate(fmt.Sprintf("notification (%d)", notification.ID), settings.Template.String)
+		if err != nil { return notice{}, err }
+		n.template = t
+	}
+	return n, nil
+}
+
+// templateSink renders the notices that don't have a template of their own
+// with its template before handing them to the sink.
+type templateSink struct {
+	sink
+	template *template.Template
+}
+
+func (s templateSink) send(n notice) error {
+	if n.template == nil { n.template = s.template }
+	return s.sink.send(n)
+}
+
+func (s templateSink) summary(held []notice) error {
+	templated := []notice{}
+	for _, n := range held {
+		if n.template == nil { n.template = s.template }
+		templated = append(templated, n)
+	}
+	return s.sink.summary(templated)
+}
+
+func (s templateSink) retryAt(attempts int64, now time.Time) (time.Time, bool) {
+	if r, ok := s.sink.(retrier); ok { return r.retryAt(attempts, now) }
+	return time.Time{}, true
+}
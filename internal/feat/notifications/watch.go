@@ -0,0 +1,264 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/config"
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/utils"
+)
+
+// maxWait bounds how long watch sleeps when it waits for the next trigger,
+// so it doesn't drift too far if the clock changes under it.
+const maxWait = time.Hour
+
+// untilNext returns how long to wait for the first occurrence, snoozed
+// delivery, repeat or retry after now, or for the quiet hours now falls in to be over.
+func untilNext(ctx context.Context, queries *sqlc.Queries, quiet config.QuietHours, now time.Time) (time.Duration, error) {
+	notifications, err := queries.GetUnfinishedNotifications(ctx)
+	if err != nil { return 0, err }
+
+	wait := maxWait
+	for _, notification := range notifications {
+		sched, err := loadSchedule(ctx, queries, notification)
+		if err != nil { continue }
+
+		occurrences := sched.between(now, now.Add(maxWait))
+		if len(occurrences) > 0 && occurrences[0].Sub(now) < wait {
+			wait = occurrences[0].Sub(now)
+		}
+	}
+
+	snoozedUntil, err := queries.GetNextSnoozedUntil(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) { return 0, err }
+	if err == nil && snoozedUntil.Time.Sub(now) < wait {
+		wait = max(snoozedUntil.Time.Sub(now), 0)
+	}
+
+	repeatAt, err := queries.GetNextRepeatAt(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) { return 0, err }
+	if err == nil && repeatAt.Time.Sub(now) < wait {
+		wait = max(repeatAt.Time.Sub(now), 0)
+	}
+
+	retryAt, err := queries.GetNextRetryAt(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) { return 0, err }
+	if err == nil && retryAt.Time.Sub(now) < wait {
+		wait = max(retryAt.Time.Sub(now), 0)
+	}
+
+	if end, ok := quiet.End(now); ok && end.Sub(now) < wait {
+		wait = end.Sub(now)
+	}
+	return wait, nil
+}
+
+// openProfileSink opens where the notifications of the profile in use go: the
+// sink given on the command line, or else its channels, falling back to its
+// sink for the notifications without one. The templates of the config file
+// render the notifications that don't have one of their own.
+func openProfileSink(ctx context.Context, queries *sqlc.Queries, c config.Config, sinkSpec string) (sink, error) {
+	templates := map[string]*template.Template{}
+	if c.Template != "" {
+		t, err := parseTemplate("template", c.Template)
+		if err != nil { return nil, err }
+		templates[""] = t
+	}
+	for channel, text := range c.ChannelTemplates {
+		t, err := parseTemplate(fmt.Sprintf("channel \"%s\"", channel), text)
+		if err != nil { return nil, fmt.Errorf("channel \"%s\": %w", channel, err) }
+		templates[channel] = t
+	}
+
+	if sinkSpec != "" || len(c.Channels) == 0 {
+		if sinkSpec == "" { sinkSpec = c.SinkFor(utils.Profile()) }
+		s, err := openSink(sinkSpec)
+		if err != nil { return nil, err }
+		if templates[""] != nil { return templateSink{sink: s, template: templates[""]}, nil }
+		return s, nil
+	}
+
+	fallbackSpec := c.SinkFor(utils.Profile())
+	fallback, err := openSink(fallbackSpec)
+	if err != nil { return nil, err }
+
+	for name, specs := range c.Channels {
+		for _, spec := range specs {
+			if err := checkSink(spec); err != nil {
+				fallback.close()
+				return nil, fmt.Errorf("channel \"%s\": %w", name, err)
+			}
+		}
+	}
+	return newRouter(ctx, queries, c, utils.Profile(), fallbackSpec, fallback, templates), nil
+}
+
+// watchDataVersion polls PRAGMA data_version on a connection of its own. The
+// value changes whenever another connection commits, so the returned channel
+// receives a value every time the database is changed by someone else.
+func watchDataVersion(ctx context.Context, db *sql.DB) (<-chan struct{}, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil { return nil, err }
+
+	var version int64
+	if err := conn.QueryRowContext(ctx, "PRAGMA data_version").Scan(&version); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer conn.Close()
+
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			var current int64
+			if err := conn.QueryRowContext(ctx, "PRAGMA data_version").Scan(&current); err != nil {
+				if ctx.Err() == nil { fmt.Fprintf(os.Stderr, "error polling database changes: %s\n", err) }
+				return
+			}
+			if current == version { continue }
+			version = current
+
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return changes, nil
+}
+
+// watch sends notifications to the sink as they become due until SIGINT or
+// SIGTERM, starting with the ones missed while it wasn't running. With a zero
+// interval it sleeps until the next trigger, waking up early when the
+// database changes. The config is read once, when watch starts.
+func watch(interval time.Duration, sinkSpec string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	c, err := config.Load()
+	if err != nil { return err }
+	quiet := c.Quiet(utils.Profile())
+
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+	defer db.Close()
+
+	queries := sqlc.New(db)
+
+	s, err := openProfileSink(ctx, queries, c, sinkSpec)
+	if err != nil { return err }
+	defer s.close()
+
+	changes, err := watchDataVersion(ctx, db)
+	if err != nil { return err }
+
+	// Whatever triggered before watch started was missed.
+	now := time.Now()
+	if err := notify(ctx, queries, s, quiet, now, now); err != nil {
+		if ctx.Err() != nil { return nil }
+		return err
+	}
+
+	last := now
+	for {
+		wait := interval
+		if wait <= 0 {
+			wait, err = untilNext(ctx, queries, quiet, last)
+			if err != nil {
+				if ctx.Err() != nil { return nil }
+				return err
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-changes:
+			timer.Stop()
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		if err := notify(ctx, queries, s, quiet, last, now); err != nil {
+			if ctx.Err() != nil { return nil }
+			return err
+		}
+		last = now
+	}
+}
+
+// check sends the due occurrences that weren't delivered yet and returns. The
+// ones that triggered more than grace ago count as missed.
+func check(grace time.Duration, sinkSpec string) error {
+	c, err := config.Load()
+	if err != nil { return err }
+	quiet := c.Quiet(utils.Profile())
+
+	ctx := context.Background()
+	db, err := utils.DbConnect(ctx)
+	if err != nil { return err }
+	defer db.Close()
+
+	queries := sqlc.New(db)
+
+	s, err := openProfileSink(ctx, queries, c, sinkSpec)
+	if err != nil { return err }
+	defer s.close()
+
+	now := time.Now()
+	return notify(ctx, queries, s, quiet, now.Add(-grace), now)
+}
+
+func watchCmd(args []string) {
+	cmd := flag.NewFlagSet("watch", flag.ExitOnError)
+	intervalFlag := cmd.Duration("interval", 0, "check every interval (e.g. 30s, 1m)\n0 sleeps until the next trigger")
+	sinkFlag := cmd.String("sink", "", "where to send notifications: " + sinkUsage + "\n(default: the channels of the config file, falling back to the sink\nof the profile, or \"stdout\")")
+
+	if err := cmd.Parse(args); err != nil {
+		fmt.Printf("error parsing cli args: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := watch(*intervalFlag, *sinkFlag); err != nil {
+		fmt.Printf("error watching notifications: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func checkCmd(args []string) {
+	cmd := flag.NewFlagSet("check", flag.ExitOnError)
+	graceFlag := cmd.Duration("grace", time.Minute, "occurrences that triggered longer ago than this were missed\nand follow the catch-up policy of their notification")
+	sinkFlag := cmd.String("sink", "", "where to send notifications: " + sinkUsage + "\n(default: the channels of the config file, falling back to the sink\nof the profile, or \"stdout\")")
+
+	if err := cmd.Parse(args); err != nil {
+		fmt.Printf("error parsing cli args: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := check(*graceFlag, *sinkFlag); err != nil {
+		fmt.Printf("error checking notifications: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,85 @@
+package notifications
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/config"
+)
+
+// webhookSink posts notifications as JSON. The X-Gmess-Event header tells
+// notifications ("notification") from summaries ("summary") apart.
+type webhookSink struct {
+	webhook config.Webhook
+	client  *http.Client
+}
+
+// openWebhookSink opens the webhook named arg in the config file, or posts
+// to arg with the default settings when it's a URL.
+func openWebhookSink(arg string) (sink, error) {
+	webhook := config.Webhook{URL: arg}
+	if !strings.HasPrefix(arg, "http://") && !strings.HasPrefix(arg, "https://") {
+		c, err := config.Load()
+		if err != nil { return nil, err }
+
+		var ok bool
+		webhook, ok = c.Webhooks[arg]
+		if !ok { return nil, fmt.Errorf("webhook \"%s\" is not in the config file", arg) }
+	}
+	return webhookSink{webhook: webhook, client: &http.Client{Timeout: webhook.TimeoutOrDefault()}}, nil
+}
+
+// post sends v to the webhook. When it has a secret the body is signed with
+// HMAC-SHA256, in the X-Gmess-Signature header as "sha256=HEX".
+func (s webhookSink) post(event string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil { return err }
+
+	req, err := http.NewRequest(http.MethodPost, s.webhook.URL, bytes.NewReader(body))
+	if err != nil { return err }
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gmess")
+	req.Header.Set("X-Gmess-Event", event)
+	for name, value := range s.webhook.Headers {
+		req.Header.Set(name, value)
+	}
+	if s.webhook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.webhook.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Gmess-Signature", "sha256=" + hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil { return err }
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64 << 10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+func (s webhookSink) send(n notice) error {
+	return s.post("notification", n.payload(time.Now()))
+}
+
+func (s webhookSink) summary(held []notice) error {
+	return s.post("summary", heldSummary(held))
+}
+
+func (s webhookSink) close() error {
+	return nil
+}
+
+func (s webhookSink) retryAt(attempts int64, now time.Time) (time.Time, bool) {
+	return backoff(s.webhook.Retries, attempts, now)
+}
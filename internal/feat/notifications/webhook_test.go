@@ -0,0 +1,140 @@
+package notifications
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matheusbucater/gmess/internal/config"
+)
+
+func TestWebhookPost(t *testing.T) {
+	_, queries := testDb(t)
+	at := time.Date(2025, 3, 1, 9, 0, 0, 0, time.Local)
+	n := testNotice(t, queries, "buy milk", at)
+
+	var req *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	s := webhookSink{
+		webhook: config.Webhook{URL: srv.URL, Secret: "s3cr3t", Headers: map[string]string{"Authorization": "Bearer token"}},
+		client: srv.Client(),
+	}
+	if err := s.send(n); err != nil { t.Fatal(err) }
+
+	if req.Method != http.MethodPost { t.Errorf("method = %s, want POST", req.Method) }
+	if got := req.Header.Get("Content-Type"); got != "application/json" { t.Errorf("Content-Type = %q", got) }
+	if got := req.Header.Get("X-Gmess-Event"); got != "notification" { t.Errorf("X-Gmess-Event = %q, want notification", got) }
+	if got := req.Header.Get("Authorization"); got != "Bearer token" { t.Errorf("Authorization = %q, want the custom header", got) }
+
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	mac.Write(body)
+	if got, want := req.Header.Get("X-Gmess-Signature"), "sha256=" + hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Errorf("X-Gmess-Signature = %q, want %q", got, want)
+	}
+
+	var p payload
+	if err := json.Unmarshal(body, &p); err != nil { t.Fatal(err) }
+	if p.MessageID != n.message.ID { t.Errorf("message_id = %d, want %d", p.MessageID, n.message.ID) }
+	if p.Text != "buy milk" { t.Errorf("text = %q, want %q", p.Text, "buy milk") }
+	if p.NotificationID != n.notification.ID { t.Errorf("notification_id = %d, want %d", p.NotificationID, n.notification.ID) }
+	if p.Type != "simple" { t.Errorf("type = %q, want simple", p.Type) }
+	if !p.OccurrenceAt.Equal(at) { t.Errorf("occurrence_at = %s, want %s", p.OccurrenceAt, at) }
+}
+
+func TestWebhookSummary(t *testing.T) {
+	_, queries := testDb(t)
+	n := testNotice(t, queries, "buy milk", time.Now())
+
+	event := ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event = r.Header.Get("X-Gmess-Event")
+	}))
+	defer srv.Close()
+
+	s := webhookSink{webhook: config.Webhook{URL: srv.URL}, client: srv.Client()}
+	if err := s.summary([]notice{n}); err != nil { t.Fatal(err) }
+	if event != "summary" { t.Errorf("X-Gmess-Event = %q, want summary", event) }
+}
+
+func TestWebhookTimeout(t *testing.T) {
+	_, queries := testDb(t)
+	n := testNotice(t, queries, "buy milk", time.Now())
+
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	testConfig(t, `{"webhooks": {"slow": {"url": "` + srv.URL + `", "timeout": "50ms"}}}`)
+	s, err := openWebhookSink("slow")
+	if err != nil { t.Fatal(err) }
+
+	start := time.Now()
+	if err := s.send(n); err == nil { t.Fatal("send succeeded, want a timeout") }
+	if elapsed := time.Since(start); elapsed > 2 * time.Second {
+		t.Errorf("send took %s, want it to time out after 50ms", elapsed)
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	db, queries := testDb(t)
+	ctx := context.Background()
+	at := time.Now().Add(-time.Minute).Truncate(time.Second)
+	n := testNotice(t, queries, "buy milk", at)
+
+	posts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	s := webhookSink{
+		webhook: config.Webhook{URL: srv.URL, Retries: config.Retries{MaxAttempts: 3, Backoff: "1m"}},
+		client: srv.Client(),
+	}
+
+	for attempt, wait := range []time.Duration{time.Minute, 2 * time.Minute} {
+		before := time.Now()
+		if err := deliver(ctx, queries, s, n.notification, n.message, at, false); err != nil { t.Fatal(err) }
+
+		delivery, err := queries.GetCurrentDelivery(ctx, n.notification.ID)
+		if err != nil { t.Fatal(err) }
+		if delivery.Outcome != "failed" { t.Fatalf("attempt %d: outcome = %s, want failed", attempt + 1, delivery.Outcome) }
+		if !delivery.RetryAt.Valid { t.Fatalf("attempt %d: retry_at not set", attempt + 1) }
+		if got := delivery.RetryAt.Time.Sub(before); got < wait - time.Second || got > wait + time.Second {
+			t.Errorf("attempt %d: retrying %s later, want %s", attempt + 1, got, wait)
+		}
+
+		// Not due yet, so it isn't retried.
+		if err := deliver(ctx, queries, s, n.notification, n.message, at, false); err != nil { t.Fatal(err) }
+		if posts != attempt + 1 { t.Fatalf("%d posts after attempt %d, want %d", posts, attempt + 1, attempt + 1) }
+
+		if _, err := db.Exec("UPDATE deliveries SET retry_at = ? WHERE id = ?", time.Now().UTC().Add(-time.Second), delivery.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := deliver(ctx, queries, s, n.notification, n.message, at, false); err != nil { t.Fatal(err) }
+	delivery, err := queries.GetCurrentDelivery(ctx, n.notification.ID)
+	if err != nil { t.Fatal(err) }
+	if delivery.Outcome != "gave_up" { t.Errorf("outcome = %s, want gave_up", delivery.Outcome) }
+	if delivery.Attempts != 3 { t.Errorf("attempts = %d, want 3", delivery.Attempts) }
+	if delivery.RetryAt.Valid { t.Errorf("retry_at = %s, want none", delivery.RetryAt.Time) }
+	if posts != 3 { t.Errorf("%d posts, want 3", posts) }
+}
@@ -0,0 +1,57 @@
+package todos
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+
+	"github.com/matheusbucater/gmess/internal/db/sqlc"
+	"github.com/matheusbucater/gmess/internal/feat"
+)
+
+type Feature struct{}
+
+func init() {
+	feat.Register(Feature{})
+}
+
+func (Feature) Name() string { return "todos" }
+
+// The todos table is part of the core migrations.
+func (Feature) Migrations() fs.FS { return nil }
+
+func (Feature) Cmd(args []string) { Cmd(args) }
+
+// Todos are removed by ON DELETE CASCADE.
+func (Feature) OnMessageDelete(ctx context.Context, queries *sqlc.Queries, msgId int64) error {
+	return nil
+}
+
+func (Feature) Details(ctx context.Context, queries *sqlc.Queries, msgId int64) (string, error) {
+	todo, err := queries.GetTodoByMessageId(ctx, msgId)
+	if err != nil { return "", err }
+
+	return fmt.Sprintf("(%d) %s", todo.ID, todo.Status), nil
+}
+
+func (f Feature) Badge(ctx context.Context, queries *sqlc.Queries, msgId int64, count int64) (string, error) {
+	return feat.DefaultBadge(f.Name()), nil
+}
+
+func (Feature) Fields(ctx context.Context, queries *sqlc.Queries, msgId int64) (map[string]any, error) {
+	todo, err := queries.GetTodoByMessageId(ctx, msgId)
+	if err != nil { return nil, err }
+
+	return map[string]any{"ID": todo.ID, "Status": todo.Status, "Done": todo.Status == e_done_status.string()}, nil
+}
+
+func (Feature) Counts(ctx context.Context, queries *sqlc.Queries) (map[int64]int64, error) {
+	rows, err := queries.CountTodosByMessage(ctx)
+	if err != nil { return nil, err }
+
+	counts := map[int64]int64{}
+	for _, row := range rows {
+		counts[row.MessageID] = row.Count
+	}
+	return counts, nil
+}
@@ -11,7 +11,6 @@ import (
 	"strings"
 
 	"github.com/matheusbucater/gmess/internal/db/sqlc"
-	"github.com/matheusbucater/gmess/internal/feat"
 	"github.com/matheusbucater/gmess/internal/utils"
 )
 
@@ -150,34 +149,6 @@ func createTodo(msgId int64) error {
 		return err
 	}
 
-	exists, err = qtx.MessageHasFeature(ctx, sqlc.MessageHasFeatureParams{
-		MessageID: msgId,
-		FeatureName: feat.E_todos_feature.String(), 
-	})
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	if exists == 1 {
-		if err := qtx.IncrementMessageFeatureCount(ctx, sqlc.IncrementMessageFeatureCountParams{
-			MessageID: msgId,
-			FeatureName: feat.E_todos_feature.String(), 
-		}); err != nil { return err }
-
-		if err := tx.Commit(); err != nil { return err }
-
-		return nil
-	}
-
-	if err = qtx.CreateMessageFeature(ctx, sqlc.CreateMessageFeatureParams{
-		MessageID: msgId,
-		FeatureName: feat.E_todos_feature.String(), 
-	}); err != nil {
-		tx.Rollback()
-		return err
-	}
-
 	if err := tx.Commit(); err != nil {
 		return err
 	}
@@ -226,12 +197,6 @@ func deleteTodo(todId int64) error {
 	if err != nil { return err }
 	if (exists == 0) { return errors.New("Invalid todo ID") }
 
-	msgId, err := queries.DeleteTodoByIdReturningMsgId(ctx, todId)
-	if err = queries.DecrementMessageFeatureCount(ctx, sqlc.DecrementMessageFeatureCountParams{
-		MessageID: msgId,
-		FeatureName: feat.E_todos_feature.String(), 
-	}); err != nil { return err }
-
 	if err = queries.DeleteTodoById(ctx, todId); err != nil { return err }
 
 	return nil
@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed 5-field cron expression: minute, hour, day of month, month
+// and day of week. Fields take "*", numbers, names (jan-dec, sun-sat), ranges
+// "a-b", steps "*/n" or "a-b/n" and comma separated lists. The day of week
+// also takes "d#n", the n-th such weekday of the month (e.g. "mon#1").
+//
+// Like in Vixie cron, when both day fields are restricted a day matches if
+// either of them does.
+type Cron struct {
+	expr    string
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	dowNth  [7]uint8 // bit n-1 set means the n-th weekday of the month
+	domStar bool
+	dowStar bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: []string{
+		"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec",
+	}}
+	cronDow = cronField{name: "day of week", min: 0, max: 7, names: []string{
+		"sun", "mon", "tue", "wed", "thu", "fri", "sat",
+	}}
+)
+
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.ToLower(s) == name { return f.min + i, nil }
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s \"%s\"", f.name, s)
+	}
+	return v, nil
+}
+
+// parse returns the bitset of the values matched by the field.
+func (f cronField) parse(s string) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(s, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step < 1 { return 0, fmt.Errorf("invalid %s step \"%s\"", f.name, stepStr) }
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(a); err != nil { return 0, err }
+			if hi, err = f.value(b); err != nil { return 0, err }
+			if lo > hi { return 0, fmt.Errorf("invalid %s range \"%s\"", f.name, rng) }
+		default:
+			v, err := f.value(rng)
+			if err != nil { return 0, err }
+			lo = v
+			if !hasStep { hi = v }
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// ParseCron parses a 5-field cron expression.
+func ParseCron(expr string) (Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Cron{}, fmt.Errorf("invalid cron expression \"%s\": expected 5 fields", expr)
+	}
+
+	c := Cron{
+		expr: strings.Join(fields, " "),
+		domStar: strings.HasPrefix(fields[2], "*"),
+		dowStar: strings.HasPrefix(fields[4], "*"),
+	}
+
+	var err error
+	if c.minute, err = cronMinute.parse(fields[0]); err != nil { return Cron{}, err }
+	if c.hour, err = cronHour.parse(fields[1]); err != nil { return Cron{}, err }
+	if c.dom, err = cronDom.parse(fields[2]); err != nil { return Cron{}, err }
+	if c.month, err = cronMonth.parse(fields[3]); err != nil { return Cron{}, err }
+
+	dow := []string{}
+	for part := range strings.SplitSeq(fields[4], ",") {
+		day, nthStr, hasNth := strings.Cut(part, "#")
+		if !hasNth {
+			dow = append(dow, part)
+			continue
+		}
+
+		wd, err := cronDow.value(day)
+		if err != nil { return Cron{}, err }
+		nth, err := strconv.Atoi(nthStr)
+		if err != nil || nth < 1 || nth > 5 {
+			return Cron{}, fmt.Errorf("invalid day of week \"%s\": expected \"d#1\" to \"d#5\"", part)
+		}
+		c.dowNth[wd % 7] |= 1 << (nth - 1)
+	}
+	if len(dow) > 0 {
+		if c.dow, err = cronDow.parse(strings.Join(dow, ",")); err != nil { return Cron{}, err }
+	}
+	// 7 is sunday too
+	if c.dow & (1 << 7) != 0 { c.dow |= 1 }
+
+	return c, nil
+}
+
+func (c Cron) String() string {
+	return c.expr
+}
+
+func (c Cron) matchesDay(t time.Time) bool {
+	wd := t.Weekday()
+	dowMatch := c.dow & (1 << wd) != 0 || c.dowNth[wd] & (1 << ((t.Day() - 1) / 7)) != 0
+	domMatch := c.dom & (1 << t.Day()) != 0
+
+	if c.domStar || c.dowStar { return domMatch && dowMatch }
+	return domMatch || dowMatch
+}
+
+// Next returns the first time after t matched by the expression, in t's
+// location. It gives up after looking five years ahead, which only happens
+// for expressions that can never match, like "0 0 30 feb *".
+func (c Cron) Next(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month & (1 << int(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month() + 1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day() + 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour & (1 << t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour() + 1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minute & (1 << t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// 01/01/2025 is a wednesday
+	date := func(month time.Month, day int, hour int, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", date(1, 1, 0, 0), date(1, 1, 0, 1)},
+		{"* * * * *", date(1, 1, 0, 0).Add(30 * time.Second), date(1, 1, 0, 1)},
+		{"0 0 1 1 *", date(1, 1, 0, 0), date(1, 1, 0, 0).AddDate(1, 0, 0)},
+
+		// steps
+		{"*/15 * * * *", date(1, 1, 0, 0), date(1, 1, 0, 15)},
+		{"*/15 * * * *", date(1, 1, 0, 50), date(1, 1, 1, 0)},
+		{"5/15 * * * *", date(1, 1, 0, 5), date(1, 1, 0, 20)},
+		{"0 9-17/4 * * *", date(1, 1, 9, 0), date(1, 1, 13, 0)},
+		{"0 9-17/4 * * *", date(1, 1, 17, 0), date(1, 2, 9, 0)},
+
+		// ranges and lists
+		{"0 9 * * 1-5", date(1, 4, 0, 0), date(1, 6, 9, 0)},
+		{"30 8 1,15 * *", date(1, 2, 0, 0), date(1, 15, 8, 30)},
+		{"0,30 8-9 * * *", date(1, 1, 8, 30), date(1, 1, 9, 0)},
+		{"0 0 1 1-3,6 *", date(3, 1, 0, 0), date(6, 1, 0, 0)},
+
+		// names
+		{"0 0 1 mar *", date(1, 1, 0, 0), date(3, 1, 0, 0)},
+		{"0 9 * * MON-fri", date(1, 4, 0, 0), date(1, 6, 9, 0)},
+		{"0 0 1 jan-feb,dec *", date(2, 1, 0, 0), date(12, 1, 0, 0)},
+		{"0 0 * * sat,sun", date(1, 1, 0, 0), date(1, 4, 0, 0)},
+		{"0 0 * * 7", date(1, 1, 0, 0), date(1, 5, 0, 0)},
+
+		// the n-th weekday of the month
+		{"0 9 * * mon#1", date(1, 1, 0, 0), date(1, 6, 9, 0)},
+		{"0 9 * * mon#1", date(1, 7, 0, 0), date(2, 3, 9, 0)},
+		{"0 9 * * fri#5", date(2, 1, 0, 0), date(5, 30, 9, 0)},
+		{"0 9 * * sun#2,sat", date(1, 1, 0, 0), date(1, 4, 9, 0)},
+		{"0 9 * * sun#2,sat", date(1, 11, 9, 0), date(1, 12, 9, 0)},
+
+		// when both day fields are restricted either one matches
+		{"0 0 13 * fri", date(1, 1, 0, 0), date(1, 3, 0, 0)},
+		{"0 0 13 * fri", date(1, 10, 0, 0), date(1, 13, 0, 0)},
+		{"0 0 13 * mon#1", date(1, 1, 0, 0), date(1, 6, 0, 0)},
+		// otherwise both must match
+		{"0 0 * * fri", date(1, 1, 0, 0), date(1, 3, 0, 0)},
+		{"0 0 */2 * fri", date(1, 1, 0, 0), date(1, 3, 0, 0)},
+		{"0 0 13 * *", date(1, 1, 0, 0), date(1, 13, 0, 0)},
+		{"0 0 13 * */1", date(1, 1, 0, 0), date(1, 13, 0, 0)},
+
+		{"0 0 29 feb *", date(1, 1, 0, 0), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q): %s", tt.expr, err)
+			continue
+		}
+		got, ok := c.Next(tt.from)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("ParseCron(%q).Next(%s) = %s, %t, want %s", tt.expr, tt.from, got, ok, tt.want)
+		}
+	}
+}
+
+func TestCronNextNever(t *testing.T) {
+	for _, expr := range []string{"0 0 30 feb *", "0 0 31 apr,jun,sep,nov *"} {
+		c, err := ParseCron(expr)
+		if err != nil { t.Fatalf("ParseCron(%q): %s", expr, err) }
+		if got, ok := c.Next(time.Now()); ok {
+			t.Errorf("ParseCron(%q).Next = %s, want it to never match", expr, got)
+		}
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"a * * * *",
+		"* * * foo *",
+		"* * * * fun",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"5-1 * * * *",
+		"1- * * * *",
+		"* * * * mon#0",
+		"* * * * mon#6",
+		"* * * * mon#",
+		"* * * * xyz#1",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestCronString(t *testing.T) {
+	c, err := ParseCron("  0  9 * *   mon#1 ")
+	if err != nil { t.Fatal(err) }
+	if got := c.String(); got != "0 9 * * mon#1" { t.Errorf("String() = %q, want %q", got, "0 9 * * mon#1") }
+}
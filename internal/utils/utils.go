@@ -7,14 +7,29 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 	"time"
+
+	"github.com/matheusbucater/gmess/internal/db/migrations"
 )
 
-var ddl string
+var dbPath string
+var profile string
+
+var profileNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 
 func LocalizeDateTime(datetime time.Time) string {
+	return localize(datetime, "Mon 02 Jan 2006 (15:04:05)")
+}
+
+func LocalizeDate(date time.Time) string {
+	return localize(date, "Mon 02 Jan 2006")
+}
+
+func localize(t time.Time, layout string) string {
 	yearReplacer := strings.NewReplacer(
 		"January", "Janeiro",
 		"February", "Fevereiro",
@@ -39,7 +54,7 @@ func LocalizeDateTime(datetime time.Time) string {
 		"Sun", "Dom",
 	)
 
-	return dayReplacer.Replace(yearReplacer.Replace(datetime.Format("Mon 02 Jan 2006 (15:04:05)")))
+	return dayReplacer.Replace(yearReplacer.Replace(t.Format(layout)))
 }
 
 func EnforceRequiredFlags(cmd *flag.FlagSet, required []string) {
@@ -53,18 +68,131 @@ func EnforceRequiredFlags(cmd *flag.FlagSet, required []string) {
 	}
 }
 
-func DbConnect(ctx context.Context) (*sql.DB, error) {
-	if err := os.MkdirAll("./data", 0755); err != nil {
+// DataDir returns the directory where gmess keeps its databases:
+// $XDG_DATA_HOME/gmess, falling back to ~/.local/share/gmess.
+func DataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "gmess"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil { return "", err }
+
+	return filepath.Join(home, ".local", "share", "gmess"), nil
+}
+
+// ResolveProfile returns the name of the profile in use, following the same
+// precedence as ResolveDbPath. It's empty when the database was given by path.
+func ResolveProfile(dbFlag string, profileFlag string) string {
+	if dbFlag != "" { return "" }
+	if profileFlag != "" { return profileFlag }
+	if os.Getenv("GMESS_DB") != "" { return "" }
+	if profile := os.Getenv("GMESS_PROFILE"); profile != "" { return profile }
+	return "default"
+}
+
+// legacyDbPath is where the default database lived before it moved to the
+// data dir.
+const legacyDbPath = "./data/messages.db"
+
+// ResolveDbPath picks the database file to use. In order of precedence:
+// the -db flag, the -profile flag, the GMESS_DB environment variable, the
+// GMESS_PROFILE environment variable and finally the default database in
+// the data dir. While the latter doesn't exist, a database left at
+// legacyDbPath is used instead, with a warning to move it.
+func ResolveDbPath(dbFlag string, profileFlag string) (string, error) {
+	if dbFlag != "" && profileFlag != "" {
+		return "", errors.New("flags -db and -profile can't be used together")
+	}
+	if dbFlag != "" { return dbFlag, nil }
+
+	profile := ResolveProfile(dbFlag, profileFlag)
+	if profile == "" { return os.Getenv("GMESS_DB"), nil }
+
+	dataDir, err := DataDir()
+	if err != nil { return "", err }
+
+	if profile == "default" {
+		path := filepath.Join(dataDir, "messages.db")
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			if _, err := os.Stat(legacyDbPath); err == nil {
+				fmt.Fprintf(os.Stderr, "warning: using the database at %s, the default one is now %s. Move it there to stop seeing this.\n", legacyDbPath, path)
+				return legacyDbPath, nil
+			}
+		}
+		return path, nil
+	}
+	if !profileNameRegexp.MatchString(profile) {
+		return "", fmt.Errorf("invalid profile name \"%s\"", profile)
+	}
+	return filepath.Join(dataDir, "profiles", profile+".db"), nil
+}
+
+// SetDbPath sets the database file used by DbOpen and DbConnect.
+func SetDbPath(path string) {
+	dbPath = path
+}
+
+// SetProfile sets the name of the profile in use, see ResolveProfile.
+func SetProfile(name string) {
+	profile = name
+}
+
+// Profile returns the name of the profile in use, "" when the database was
+// given by path.
+func Profile() string {
+	return profile
+}
+
+// DbOpen opens the database without touching its schema.
+func DbOpen(ctx context.Context) (*sql.DB, error) {
+	if dbPath == "" {
+		path, err := ResolveDbPath("", "")
+		if err != nil { return nil, err }
+		dbPath = path
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
     	return nil, fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-    db, err := sql.Open("sqlite", "file:./data/messages.db?_foreign_keys=1&_journal_mode=WAL&mode=rwc")	
+	// modernc.org/sqlite only reads pragmas given as _pragma=name(value), and
+	// runs them on every new connection of the pool.
+	dsn := "file:" + dbPath + "?mode=rwc" +
+		"&_pragma=foreign_keys(1)" +
+		"&_pragma=journal_mode(WAL)" +
+		"&_pragma=busy_timeout(5000)"
+
+	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, err
 	}
-	if _, err := db.ExecContext(ctx, ddl); err != nil {
+
+	var foreignKeys int64
+	if err := db.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if foreignKeys != 1 {
+		db.Close()
+		return nil, errors.New("foreign keys are not enforced by the sqlite driver")
+	}
+
+	return db, nil
+}
+
+// DbConnect opens the database and applies any pending migration of every
+// migrations source.
+func DbConnect(ctx context.Context) (*sql.DB, error) {
+	db, err := DbOpen(ctx)
+	if err != nil {
 		return nil, err
 	}
+	for _, src := range migrations.Sources() {
+		if err := migrations.Up(ctx, db, src); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
 
 	return db, nil
 }